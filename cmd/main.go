@@ -3,27 +3,87 @@ package main
 import (
 	"context"
 	"fmt"
-	"net/http"
 	"os"
 	"os/signal"
+	"time"
 
 	"github.com/alecthomas/kong"
 	"github.com/spf13/viper"
 
 	"nitro/markdown-link-check/internal"
+	"nitro/markdown-link-check/internal/service/cache"
+	"nitro/markdown-link-check/internal/service/provider"
 )
 
+type configProviderWeb struct {
+	Header map[string][]string `mapstructure:"header"`
+	// The rate limit and the retry are pointers, so an overwrite can disable them by setting them to zero.
+	RateLimit *struct {
+		RequestsPerSecond float64 `mapstructure:"requestsPerSecond"`
+		Burst             int     `mapstructure:"burst"`
+	} `mapstructure:"rateLimit"`
+	Retry *struct {
+		Max        int           `mapstructure:"max"`
+		Backoff    time.Duration `mapstructure:"backoff"`
+		MaxBackoff time.Duration `mapstructure:"maxBackoff"`
+	} `mapstructure:"retry"`
+	StatusCodes []int `mapstructure:"statusCodes"`
+	Redirect    struct {
+		StatusCodes []int `mapstructure:"statusCodes"`
+		MaxHops     int   `mapstructure:"maxHops"`
+		CrossDomain *bool `mapstructure:"crossDomain"`
+	} `mapstructure:"redirect"`
+	// Only the mode can be overwritten per endpoint, the other browser options are global.
+	Browser struct {
+		Mode      string        `mapstructure:"mode"`
+		PoolSize  int           `mapstructure:"poolSize"`
+		Timeout   time.Duration `mapstructure:"timeout"`
+		RemoteURL string        `mapstructure:"remoteURL"`
+	} `mapstructure:"browser"`
+}
+
+func (c configProviderWeb) webConfig() provider.WebConfig {
+	cfg := provider.WebConfig{
+		Header: c.Header,
+		Redirect: provider.WebConfigRedirect{
+			StatusCodes: c.Redirect.StatusCodes,
+			MaxHops:     c.Redirect.MaxHops,
+			CrossDomain: c.Redirect.CrossDomain,
+		},
+		StatusCodes: c.StatusCodes,
+		BrowserMode: c.Browser.Mode,
+	}
+	if c.RateLimit != nil {
+		cfg.RateLimit = &provider.WebConfigRateLimit{
+			RequestsPerSecond: c.RateLimit.RequestsPerSecond,
+			Burst:             c.RateLimit.Burst,
+		}
+	}
+	if c.Retry != nil {
+		cfg.Retry = &provider.WebConfigRetry{
+			Max:        c.Retry.Max,
+			Backoff:    c.Retry.Backoff,
+			MaxBackoff: c.Retry.MaxBackoff,
+		}
+	}
+	return cfg
+}
+
 type config struct {
 	Ignore struct {
 		Link []string `mapstructure:"link"`
 		File []string `mapstructure:"file"`
 	} `mapstructure:"ignore"`
+	Markdown struct {
+		Extensions []string `mapstructure:"extensions"`
+		Parser     string   `mapstructure:"parser"`
+	} `mapstructure:"markdown"`
 	Provider struct {
 		Web struct {
-			Header    map[string][]string `mapstructure:"header"`
-			Overwrite []struct {
-				Endpoint string              `mapstructure:"endpoint"`
-				Header   map[string][]string `mapstructure:"header"`
+			configProviderWeb `mapstructure:",squash"`
+			Overwrite         []struct {
+				configProviderWeb `mapstructure:",squash"`
+				Endpoint          string `mapstructure:"endpoint"`
 			} `mapstructure:"overwrite"`
 		} `mapstructure:"web"`
 		GitHub map[string]struct {
@@ -31,12 +91,33 @@ type config struct {
 			Token string `mapstructure:"token"`
 		} `mapstructure:"github"`
 	} `mapstructure:"provider"`
+	Worker struct {
+		Concurrency int            `mapstructure:"concurrency"`
+		Provider    map[string]int `mapstructure:"provider"`
+		ErrorPolicy string         `mapstructure:"errorPolicy"`
+	} `mapstructure:"worker"`
+	Cache struct {
+		Path string `mapstructure:"path"`
+		TTL  map[string]struct {
+			Valid   time.Duration `mapstructure:"valid"`
+			Invalid time.Duration `mapstructure:"invalid"`
+		} `mapstructure:"ttl"`
+	} `mapstructure:"cache"`
 }
 
 func main() {
 	var params struct {
 		Path   string `help:"Path to be processed" required:"true" arg:"true" type:"string"`
 		Config string `help:"Path to the configuration file." required:"true" short:"c" type:"string"`
+		Format string `help:"Format of the report (text, json, sarif, junit, github)." default:"text" enum:"text,json,sarif,junit,github" short:"f"` // nolint: lll
+		Output string `help:"Path to the file where the report is written, defaults to the standard output." short:"o"`
+
+		ChangedSince string `help:"Only check the Markdown files added or modified since the git reference." placeholder:"REF"`
+
+		Baseline       string `help:"Path to the baseline file with the known invalid links, they don't fail the execution." placeholder:"FILE"` // nolint: lll
+		UpdateBaseline bool   `help:"Write the current invalid links to the baseline file."`
+
+		Fix bool `help:"Rewrite the permanently redirected links at the Markdown files to their final location."`
 	}
 	kong.Parse(&params, kong.Name("markdown-link-check"))
 
@@ -45,13 +126,27 @@ func main() {
 		handleError("fail to configure the client: %s", err.Error())
 	}
 	client.Path = params.Path
+	client.Format = params.Format
+	client.ChangedSince = params.ChangedSince
+	client.Baseline = params.Baseline
+	client.UpdateBaseline = params.UpdateBaseline
+	client.Fix = params.Fix
+
+	if params.Output != "" {
+		f, err := os.Create(params.Output)
+		if err != nil {
+			handleError("fail to create the output file: %s", err.Error())
+		}
+		defer f.Close()
+		client.Output = f
+	}
 
-	hasInvalidLinks, err := client.Run(executionContext())
+	hasFailure, err := client.Run(executionContext())
 	if err != nil {
 		handleError("fail at client execution: %s", err.Error())
 	}
-	if hasInvalidLinks {
-		os.Exit(1)
+	if hasFailure {
+		os.Exit(1) // nolint: gocritic
 	}
 }
 
@@ -82,11 +177,24 @@ func configClient(path string) (internal.Client, error) {
 	}
 
 	web := internal.ClientProviderWeb{
-		Config:          cfg.Provider.Web.Header,
-		ConfigOverwrite: make(map[string]http.Header, len(cfg.Provider.Web.Overwrite)),
+		Config:          cfg.Provider.Web.webConfig(),
+		ConfigOverwrite: make([]provider.WebConfigOverwrite, 0, len(cfg.Provider.Web.Overwrite)),
+		Browser: provider.WebBrowser{
+			PoolSize:  cfg.Provider.Web.Browser.PoolSize,
+			Timeout:   cfg.Provider.Web.Browser.Timeout,
+			RemoteURL: cfg.Provider.Web.Browser.RemoteURL,
+		},
 	}
 	for _, overwrite := range cfg.Provider.Web.Overwrite {
-		web.ConfigOverwrite[overwrite.Endpoint] = overwrite.Header
+		web.ConfigOverwrite = append(web.ConfigOverwrite, provider.WebConfigOverwrite{
+			Endpoint: overwrite.Endpoint,
+			Config:   overwrite.webConfig(),
+		})
+	}
+
+	ttl := make(map[string]cache.TTL, len(cfg.Cache.TTL))
+	for name, value := range cfg.Cache.TTL {
+		ttl[name] = cache.TTL{Valid: value.Valid, Invalid: value.Invalid}
 	}
 
 	return internal.Client{
@@ -94,10 +202,23 @@ func configClient(path string) (internal.Client, error) {
 			File: cfg.Ignore.File,
 			Link: cfg.Ignore.Link,
 		},
+		Markdown: internal.ClientMarkdown{
+			Extensions: cfg.Markdown.Extensions,
+			Parser:     cfg.Markdown.Parser,
+		},
 		Provider: internal.ClientProvider{
 			Github: github,
 			Web:    web,
 		},
+		Worker: internal.ClientWorker{
+			Concurrency:         cfg.Worker.Concurrency,
+			ProviderConcurrency: cfg.Worker.Provider,
+			ErrorPolicy:         cfg.Worker.ErrorPolicy,
+		},
+		Cache: internal.ClientCache{
+			Path: cfg.Cache.Path,
+			TTL:  ttl,
+		},
 	}, nil
 }
 
@@ -4,16 +4,21 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"sort"
 	"strings"
-
-	"github.com/logrusorgru/aurora"
+	"time"
 
 	"nitro/markdown-link-check/internal/service"
+	"nitro/markdown-link-check/internal/service/baseline"
+	"nitro/markdown-link-check/internal/service/cache"
+	"nitro/markdown-link-check/internal/service/fix"
+	"nitro/markdown-link-check/internal/service/git"
 	"nitro/markdown-link-check/internal/service/parser"
 	"nitro/markdown-link-check/internal/service/provider"
+	"nitro/markdown-link-check/internal/service/report"
 	"nitro/markdown-link-check/internal/service/scan"
 	"nitro/markdown-link-check/internal/service/worker"
 )
@@ -24,6 +29,12 @@ type ClientIgnore struct {
 	File []string
 }
 
+// ClientMarkdown holds the configuration for the Markdown files. The 'Parser' defaults to blackfriday.
+type ClientMarkdown struct {
+	Extensions []string
+	Parser     string
+}
+
 // ClientProviderGithub holds the configuration for the GitHub provider.
 type ClientProviderGithub struct {
 	Token      string
@@ -33,8 +44,22 @@ type ClientProviderGithub struct {
 
 // ClientProviderWeb holds the configuration for the web provider.
 type ClientProviderWeb struct {
-	Config          http.Header
-	ConfigOverwrite map[string]http.Header
+	Config          provider.WebConfig
+	ConfigOverwrite []provider.WebConfigOverwrite
+	Browser         provider.WebBrowser
+}
+
+// ClientWorker holds the configuration for the worker.
+type ClientWorker struct {
+	Concurrency         int
+	ProviderConcurrency map[string]int
+	ErrorPolicy         string
+}
+
+// ClientCache holds the configuration for the results cache. The cache is disabled when 'Path' is empty.
+type ClientCache struct {
+	Path string
+	TTL  map[string]cache.TTL
 }
 
 // ClientProvider holds the configuration for the providers.
@@ -43,41 +68,143 @@ type ClientProvider struct {
 	Web    ClientProviderWeb
 }
 
+// Formats available to report the execution.
+const (
+	ClientFormatText   = "text"
+	ClientFormatJSON   = "json"
+	ClientFormatSARIF  = "sarif"
+	ClientFormatJUnit  = "junit"
+	ClientFormatGitHub = "github"
+)
+
+// Parsers available to process the Markdown files.
+const (
+	ClientParserBlackfriday = "blackfriday"
+	ClientParserGFM         = "gfm"
+)
+
+type clientParser interface {
+	Do(payload []byte) []byte
+	Links(document parser.Document) []parser.Link
+	SanitizedAnchorName(text string) string
+}
+
+type clientReporter interface {
+	Report(w io.Writer, execution report.Execution) error
+}
+
 // Client is responsible to bootstrap the application.
 type Client struct {
-	Path     string
+	Path   string
+	Format string
+
+	// Only the Markdown files added or modified since the git reference are checked when set.
+	ChangedSince string
+
+	// The invalid links at the baseline file are known and don't fail the execution. The baseline file is written
+	// with the current invalid links instead when 'UpdateBaseline' is set.
+	Baseline       string
+	UpdateBaseline bool
+
+	// The permanently redirected links are rewritten at the Markdown files to their final location when set.
+	Fix bool
+
+	// The report is written here, it defaults to the standard output.
+	Output io.Writer
+
 	Ignore   ClientIgnore
+	Markdown ClientMarkdown
 	Provider ClientProvider
+	Worker   ClientWorker
+	Cache    ClientCache
 
-	parser    parser.Markdown
+	parser    clientParser
 	providers []worker.Provider
+	reporter  clientReporter
+	web       provider.Web
 }
 
 // Run starts the application execution.
 func (c Client) Run(ctx context.Context) (bool, error) {
-	if err := c.init(); err != nil {
+	start := time.Now()
+	if err := c.init(ctx); err != nil {
 		return false, fmt.Errorf("fail during init: %w", err)
 	}
+	defer c.web.Close()
 
-	s := scan.Scan{IgnoreFile: c.Ignore.File, IgnoreLink: c.Ignore.Link, Parser: c.parser}
+	s := scan.Scan{
+		IgnoreFile: c.Ignore.File,
+		IgnoreLink: c.Ignore.Link,
+		Extensions: c.markdownExtensions(),
+		Parser:     c.parser,
+	}
 	if err := s.Init(); err != nil {
 		return false, fmt.Errorf("fail to initialize the scan service: %w", err)
 	}
-	entries, err := s.Process(c.Path)
+	files, err := s.Files(c.Path)
+	if err != nil {
+		return false, fmt.Errorf("fail to list the files: %w", err)
+	}
+	if c.ChangedSince != "" {
+		if files, err = c.changedFiles(ctx, s, files); err != nil {
+			return false, fmt.Errorf("fail to restrict the files to the changed ones: %w", err)
+		}
+	}
+	entries, err := s.Process(files)
 	if err != nil {
 		return false, fmt.Errorf("fail to scan the files: %w", err)
 	}
 
-	w := worker.Worker{Providers: c.providers}
+	w := worker.Worker{
+		Providers:           c.providers,
+		Concurrency:         c.Worker.Concurrency,
+		ProviderConcurrency: c.Worker.ProviderConcurrency,
+		ErrorPolicy:         c.Worker.ErrorPolicy,
+	}
+	var resultCache *cache.Cache
+	if c.Cache.Path != "" {
+		resultCache = &cache.Cache{Path: c.Cache.Path, TTL: c.Cache.TTL}
+		if err := resultCache.Init(); err != nil {
+			return false, fmt.Errorf("fail to initialize the cache: %w", err)
+		}
+		w.Cache = resultCache
+	}
 	entries, err = w.Process(ctx, entries)
 	if err != nil {
 		return false, fmt.Errorf("fail to process the link: %w", err)
 	}
+	if resultCache != nil {
+		if err := resultCache.Save(); err != nil {
+			return false, fmt.Errorf("fail to save the cache: %w", err)
+		}
+	}
+	if c.Fix {
+		if entries, err = (fix.Fix{}).Apply(entries); err != nil {
+			return false, fmt.Errorf("fail to fix the links: %w", err)
+		}
+	}
+
+	var fixed []service.Entry
+	if c.Baseline != "" {
+		if entries, fixed, err = c.applyBaseline(files, entries); err != nil {
+			return false, fmt.Errorf("fail to apply the baseline: %w", err)
+		}
+	}
 
-	return c.output(entries), nil
+	execution := report.Execution{
+		Path:     c.Path,
+		Files:    files,
+		Entries:  entries,
+		Fixed:    fixed,
+		Duration: time.Since(start),
+	}
+	if err := c.reporter.Report(c.Output, execution); err != nil {
+		return false, fmt.Errorf("fail to report the execution: %w", err)
+	}
+	return c.hasFailure(entries), nil
 }
 
-func (c *Client) init() error {
+func (c *Client) init(ctx context.Context) error {
 	if c.Path == "" {
 		return errors.New("missing 'path")
 	}
@@ -90,6 +217,17 @@ func (c *Client) init() error {
 		return errors.New("path is expected to be a directory")
 	}
 
+	if c.UpdateBaseline && (c.Baseline == "") {
+		return errors.New("missing 'baseline' to be updated")
+	}
+	if c.UpdateBaseline && (c.ChangedSince != "") {
+		return errors.New("the baseline can't be updated at the incremental mode because not all the files are checked")
+	}
+
+	if err := c.initReporter(ctx); err != nil {
+		return fmt.Errorf("fail to initialize the reporter: %w", err)
+	}
+
 	var email provider.Email
 	if err := email.Init(); err != nil {
 		return fmt.Errorf("fail to iniitalize the email provider: %w", err)
@@ -108,23 +246,21 @@ func (c *Client) init() error {
 		c.providers = append(c.providers, client)
 	}
 
-	webConfigOverwrites := make(map[string]provider.WebConfig, len(c.Provider.Web.ConfigOverwrite))
-	for key, value := range c.Provider.Web.ConfigOverwrite {
-		webConfigOverwrites[key] = provider.WebConfig{Header: value}
-	}
 	w := provider.Web{
-		Config:          provider.WebConfig{Header: c.Provider.Web.Config},
-		ConfigOverwrite: webConfigOverwrites,
+		Config:          c.Provider.Web.Config,
+		ConfigOverwrite: c.Provider.Web.ConfigOverwrite,
+		Browser:         c.Provider.Web.Browser,
 	}
 	if err := w.Init(); err != nil {
 		return fmt.Errorf("fail to initialize the web provider: %w", err)
 	}
 	c.providers = append(c.providers, w)
+	c.web = w
 
-	var p parser.Markdown
-	p.Init()
-	c.parser = p
-	f := provider.File{Path: c.Path, Parser: p}
+	if err := c.initParser(); err != nil {
+		return fmt.Errorf("fail to initialize the parser: %w", err)
+	}
+	f := provider.File{Path: c.Path, Parser: c.parser, Extensions: c.markdownExtensions()}
 	if err := f.Init(); err != nil {
 		return fmt.Errorf("fail to initialize the file provider: %w", err)
 	}
@@ -133,90 +269,136 @@ func (c *Client) init() error {
 	return nil
 }
 
-func (c Client) output(entries []service.Entry) bool {
-	var (
-		result bool
-		iter   = c.aggregate(entries)
-	)
-	for {
-		key, entries, ok := iter()
-		if !ok {
-			break
-		}
-		if !c.hasInvalidLink(entries) {
-			continue
-		}
-		if !result {
-			result = true
-		}
-
-		fmt.Print(aurora.Bold(c.relativePath(key)))
-		for _, entry := range entries {
-			if entry.Valid {
-				continue
-			}
-			fmt.Printf("\n%s %s", aurora.Bold(aurora.Gray(24, "-")), entry.Link)
+func (c Client) applyBaseline(
+	files []string, entries []service.Entry,
+) ([]service.Entry, []service.Entry, error) {
+	b := baseline.Baseline{Path: c.Baseline, Root: c.Path}
+	if c.UpdateBaseline {
+		if err := b.Write(entries); err != nil {
+			return nil, nil, fmt.Errorf("fail to write the baseline: %w", err)
 		}
-		fmt.Printf("\n\n")
 	}
-	return result
+	if err := b.Init(); err != nil {
+		return nil, nil, fmt.Errorf("fail to initialize the baseline: %w", err)
+	}
+	entries, fixed := b.Apply(files, entries)
+	return entries, fixed, nil
 }
 
-func (Client) aggregate(entries []service.Entry) func() (string, []service.Entry, bool) {
-	var (
-		keys   = make([]string, 0, len(entries))
-		result = make(map[string][]service.Entry)
-	)
+// changedFiles returns the files added or modified since the reference and the files with relative links to any of
+// the changed or deleted paths, because they may be broken by the change, like a file being moved.
+func (c Client) changedFiles(ctx context.Context, s scan.Scan, files []string) ([]string, error) {
+	changes, err := git.Git{Path: c.Path}.Changes(ctx, c.ChangedSince)
+	if err != nil {
+		return nil, fmt.Errorf("fail to list the changes since '%s': %w", c.ChangedSince, err)
+	}
 
-	for _, entry := range entries {
-		if _, ok := result[entry.Path]; !ok {
-			keys = append(keys, entry.Path)
-			result[entry.Path] = make([]service.Entry, 0)
+	var changedPaths, paths []string
+	for _, change := range changes {
+		changedPaths = append(changedPaths, change.Path)
+		if change.Status != git.StatusDeleted {
+			paths = append(paths, change.Path)
 		}
-		result[entry.Path] = append(result[entry.Path], entry)
 	}
-	sort.Strings(keys)
 
-	var index = 0
-	return func() (string, []service.Entry, bool) {
-		if index >= len(keys) {
-			return "", nil, false
+	changedFiles, err := s.Restrict(files, paths)
+	if err != nil {
+		return nil, fmt.Errorf("fail to restrict the files: %w", err)
+	}
+
+	index, err := s.Index(files)
+	if err != nil {
+		return nil, fmt.Errorf("fail to index the links: %w", err)
+	}
+	inboundFiles, err := index.Inbound(changedPaths)
+	if err != nil {
+		return nil, fmt.Errorf("fail to find the inbound links: %w", err)
+	}
+
+	result := make([]string, 0, len(changedFiles)+len(inboundFiles))
+	seen := make(map[string]struct{}, cap(result))
+	for _, file := range append(changedFiles, inboundFiles...) {
+		if _, ok := seen[file]; ok {
+			continue
 		}
-		key := keys[index]
-		index++
-		entries := result[key]
-		sort.Sort(serviceEntrySort(entries))
-		return key, entries, true
+		seen[file] = struct{}{}
+		result = append(result, file)
 	}
+	sort.Strings(result)
+	return result, nil
 }
 
-func (Client) hasInvalidLink(entries []service.Entry) bool {
-	for _, entry := range entries {
-		if !entry.Valid {
-			return true
+// markdownExtensions returns the Markdown extensions with the leading dot.
+func (c Client) markdownExtensions() []string {
+	extensions := make([]string, 0, len(c.Markdown.Extensions))
+	for _, extension := range c.Markdown.Extensions {
+		if !strings.HasPrefix(extension, ".") {
+			extension = "." + extension
 		}
+		extensions = append(extensions, extension)
 	}
-	return false
+	return extensions
 }
 
-func (c Client) relativePath(path string) string {
-	dirPath := c.Path
-	if !strings.HasSuffix(dirPath, "/") {
-		dirPath += "/"
+func (c *Client) initParser() error {
+	switch c.Markdown.Parser {
+	case "", ClientParserBlackfriday:
+		var p parser.Markdown
+		p.Init()
+		c.parser = p
+	case ClientParserGFM:
+		var p parser.GFM
+		p.Init()
+		c.parser = p
+	default:
+		return fmt.Errorf("unknown parser '%s'", c.Markdown.Parser)
 	}
-	return strings.TrimPrefix(path, dirPath)
+	return nil
 }
 
-type serviceEntrySort []service.Entry
+func (c *Client) initReporter(ctx context.Context) error {
+	if c.Output == nil {
+		c.Output = os.Stdout
+	}
 
-func (s serviceEntrySort) Len() int {
-	return len(s)
+	switch c.Format {
+	case "", ClientFormatText:
+		c.reporter = report.Text{Color: c.Output == os.Stdout}
+	case ClientFormatJSON:
+		c.reporter = report.JSON{}
+	case ClientFormatSARIF:
+		c.reporter = report.SARIF{Workspace: c.workspace(ctx)}
+	case ClientFormatJUnit:
+		c.reporter = report.JUnit{}
+	case ClientFormatGitHub:
+		c.reporter = report.GitHub{Workspace: c.workspace(ctx)}
+	default:
+		return fmt.Errorf("unknown format '%s'", c.Format)
+	}
+	return nil
 }
 
-func (s serviceEntrySort) Swap(i, j int) {
-	s[i], s[j] = s[j], s[i]
+// workspace returns the repository root, where the reported file paths are relative to. It's the GitHub Actions
+// workspace when set, otherwise the root of the Git repository of the path or the working directory.
+func (c Client) workspace(ctx context.Context) string {
+	if workspace := os.Getenv("GITHUB_WORKSPACE"); workspace != "" {
+		return workspace
+	}
+	if root, err := (git.Git{Path: c.Path}).Root(ctx); err == nil {
+		return root
+	}
+	workspace, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	return workspace
 }
 
-func (s serviceEntrySort) Less(i, j int) bool {
-	return s[i].Link < s[j].Link
+func (Client) hasFailure(entries []service.Entry) bool {
+	for _, entry := range entries {
+		if entry.Failed() {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,165 @@
+package baseline
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"nitro/markdown-link-check/internal/service"
+)
+
+const baselineVersion = 1
+
+type baselineFile struct {
+	Version int             `json:"version"`
+	Entries []baselineEntry `json:"entries"`
+}
+
+type baselineEntry struct {
+	File   string `json:"file"`
+	Link   string `json:"link"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// Baseline holds the known invalid entries that are accepted and don't fail the execution. The files are stored
+// relative to 'Root', so the baseline file can be shared between different environments.
+type Baseline struct {
+	Path string
+	Root string
+
+	entries []baselineEntry
+}
+
+// Init the internal state and load the baseline file.
+func (b *Baseline) Init() error {
+	if b.Path == "" {
+		return errors.New("missing 'path'")
+	}
+	if b.Root == "" {
+		return errors.New("missing 'root'")
+	}
+
+	payload, err := ioutil.ReadFile(b.Path)
+	if err != nil {
+		return fmt.Errorf("fail to read the baseline file: %w", err)
+	}
+
+	var file baselineFile
+	if err := json.Unmarshal(payload, &file); err != nil {
+		return fmt.Errorf("fail to decode the baseline file: %w", err)
+	}
+	if file.Version != baselineVersion {
+		return fmt.Errorf("unsupported baseline version '%d'", file.Version)
+	}
+	b.entries = file.Entries
+	return nil
+}
+
+// Write the invalid entries to the baseline file. Errored entries are not written because they're not known failures.
+func (b Baseline) Write(entries []service.Entry) error {
+	var (
+		index = make(map[baselineEntry]struct{})
+		file  = baselineFile{Version: baselineVersion, Entries: make([]baselineEntry, 0)}
+	)
+	for _, entry := range entries {
+		if entry.Valid || entry.Errored || (entry.Severity != service.SeverityError) {
+			continue
+		}
+		item := baselineEntry{File: b.relativePath(entry.Path), Link: entry.Link, Reason: string(entry.Reason)}
+		if _, ok := index[item]; ok {
+			continue
+		}
+		index[item] = struct{}{}
+		file.Entries = append(file.Entries, item)
+	}
+	sort.Slice(file.Entries, func(i, j int) bool {
+		if file.Entries[i].File != file.Entries[j].File {
+			return file.Entries[i].File < file.Entries[j].File
+		}
+		return file.Entries[i].Link < file.Entries[j].Link
+	})
+
+	payload, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("fail to encode the baseline file: %w", err)
+	}
+	if err := ioutil.WriteFile(b.Path, append(payload, '\n'), 0644); err != nil { // nolint: gosec
+		return fmt.Errorf("fail to write the baseline file: %w", err)
+	}
+	return nil
+}
+
+// Apply the baseline to the entries. The invalid entries present at the baseline are marked and don't fail the
+// execution anymore. The baseline entries are matched by file and link, the reason is informative only.
+//
+// The baseline entries that are fixed are returned, so they can be removed from the baseline. Only the entries from
+// the processed files, or from files that don't exist anymore, are considered as fixed.
+func (b Baseline) Apply(files []string, entries []service.Entry) ([]service.Entry, []service.Entry) {
+	type key struct {
+		file string
+		link string
+	}
+
+	known := make(map[key]struct{}, len(b.entries))
+	for _, entry := range b.entries {
+		known[key{file: entry.File, link: entry.Link}] = struct{}{}
+	}
+
+	var (
+		result  = make([]service.Entry, 0, len(entries))
+		invalid = make(map[key]struct{})
+	)
+	for _, entry := range entries {
+		k := key{file: b.relativePath(entry.Path), link: entry.Link}
+		if entry.Valid {
+			result = append(result, entry)
+			continue
+		}
+
+		// Errored entries are not marked, but they avoid the baseline entry being reported as fixed.
+		invalid[k] = struct{}{}
+		if _, ok := known[k]; ok && !entry.Errored {
+			entry.Baseline = true
+		}
+		result = append(result, entry)
+	}
+
+	processed := make(map[string]struct{}, len(files))
+	for _, file := range files {
+		processed[b.relativePath(file)] = struct{}{}
+	}
+
+	var fixed []service.Entry
+	for _, entry := range b.entries {
+		if _, ok := invalid[key{file: entry.File, link: entry.Link}]; ok {
+			continue
+		}
+		path := filepath.Join(b.Root, filepath.FromSlash(entry.File))
+		if _, ok := processed[entry.File]; !ok && b.exists(path) {
+			continue
+		}
+		fixed = append(fixed, service.Entry{
+			Path:   path,
+			Link:   entry.Link,
+			Result: service.Result{Valid: true, Reason: service.Reason(entry.Reason)},
+		})
+	}
+	return result, fixed
+}
+
+func (b Baseline) relativePath(path string) string {
+	relPath, err := filepath.Rel(b.Root, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(relPath)
+}
+
+func (Baseline) exists(path string) bool {
+	_, err := os.Stat(path)
+	return !os.IsNotExist(err)
+}
@@ -0,0 +1,138 @@
+package baseline
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"nitro/markdown-link-check/internal/service"
+)
+
+func TestBaselineInit(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "baseline")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	invalidPath := filepath.Join(dir, "invalid.json")
+	require.NoError(t, ioutil.WriteFile(invalidPath, []byte(`{"version": 2, "entries": []}`), 0600))
+
+	tests := []struct {
+		message   string
+		path      string
+		root      string
+		shouldErr bool
+	}{
+		{
+			message:   "have an error because of a missing path",
+			root:      dir,
+			shouldErr: true,
+		},
+		{
+			message:   "have an error because of a missing root",
+			path:      invalidPath,
+			shouldErr: true,
+		},
+		{
+			message:   "have an error because of a missing baseline file",
+			path:      filepath.Join(dir, "missing.json"),
+			root:      dir,
+			shouldErr: true,
+		},
+		{
+			message:   "have an error because of an unsupported version",
+			path:      invalidPath,
+			root:      dir,
+			shouldErr: true,
+		},
+	}
+
+	for i := 0; i < len(tests); i++ {
+		tt := tests[i]
+		t.Run("Should "+tt.message, func(t *testing.T) {
+			baseline := Baseline{Path: tt.path, Root: tt.root}
+			require.Equal(t, tt.shouldErr, (baseline.Init() != nil))
+		})
+	}
+}
+
+func TestBaselineApply(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "baseline")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var (
+		path     = filepath.Join(dir, "baseline.json")
+		fileA    = filepath.Join(dir, "a.md")
+		fileB    = filepath.Join(dir, "docs/b.md")
+		notFound = service.Result{Severity: service.SeverityError, Provider: "file", Reason: service.ReasonNotFound}
+	)
+	require.NoError(t, ioutil.WriteFile(fileA, nil, 0600))
+
+	baseline := Baseline{Path: path, Root: dir}
+	require.NoError(t, baseline.Write([]service.Entry{
+		{Path: fileA, Link: "known.md", Result: notFound},
+		{Path: fileA, Link: "known.md", Result: notFound},
+		{Path: fileA, Link: "fixed.md", Result: notFound},
+		{Path: fileA, Link: "flaky.md", Result: notFound},
+		{Path: fileA, Link: "valid.md", Result: service.Result{Valid: true}},
+		{Path: fileA, Link: "errored.md", Result: service.Result{Errored: true, Severity: service.SeverityError}},
+		{Path: fileB, Link: "deleted.md", Result: notFound},
+	}))
+
+	payload, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, `{
+  "version": 1,
+  "entries": [
+    {
+      "file": "a.md",
+      "link": "fixed.md",
+      "reason": "not-found"
+    },
+    {
+      "file": "a.md",
+      "link": "flaky.md",
+      "reason": "not-found"
+    },
+    {
+      "file": "a.md",
+      "link": "known.md",
+      "reason": "not-found"
+    },
+    {
+      "file": "docs/b.md",
+      "link": "deleted.md",
+      "reason": "not-found"
+    }
+  ]
+}
+`, string(payload))
+
+	require.NoError(t, baseline.Init())
+	entries, fixed := baseline.Apply([]string{fileA}, []service.Entry{
+		{Path: fileA, Link: "known.md", Result: notFound},
+		{Path: fileA, Link: "new.md", Result: notFound},
+		{Path: fileA, Link: "flaky.md", Result: service.Result{Errored: true, Severity: service.SeverityError}},
+		{Path: fileA, Link: "fixed.md", Result: service.Result{Valid: true}},
+	})
+
+	known := notFound
+	known.Baseline = true
+	require.Equal(t, []service.Entry{
+		{Path: fileA, Link: "known.md", Result: known},
+		{Path: fileA, Link: "new.md", Result: notFound},
+		{Path: fileA, Link: "flaky.md", Result: service.Result{Errored: true, Severity: service.SeverityError}},
+		{Path: fileA, Link: "fixed.md", Result: service.Result{Valid: true}},
+	}, entries)
+	require.Equal(t, []service.Entry{
+		{Path: fileA, Link: "fixed.md", Result: service.Result{Valid: true, Reason: service.ReasonNotFound}},
+		{Path: fileB, Link: "deleted.md", Result: service.Result{Valid: true, Reason: service.ReasonNotFound}},
+	}, fixed)
+}
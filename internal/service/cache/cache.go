@@ -0,0 +1,191 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"nitro/markdown-link-check/internal/service"
+)
+
+const cacheVersion = 1
+
+// TTL holds how long the results are kept at the cache. The results are not cached when the duration is zero.
+type TTL struct {
+	Valid   time.Duration
+	Invalid time.Duration
+}
+
+type cacheFile struct {
+	Version int                   `json:"version"`
+	Entries map[string]cacheEntry `json:"entries"`
+}
+
+type cacheEntry struct {
+	Provider   string          `json:"provider"`
+	Valid      bool            `json:"valid"`
+	Severity   string          `json:"severity,omitempty"`
+	Reason     string          `json:"reason,omitempty"`
+	StatusCode int             `json:"statusCode,omitempty"`
+	Message    string          `json:"message,omitempty"`
+	Redirects  []cacheRedirect `json:"redirects,omitempty"`
+	CheckedAt  time.Time       `json:"checkedAt"`
+}
+
+type cacheRedirect struct {
+	Link       string `json:"link"`
+	StatusCode int    `json:"statusCode"`
+}
+
+// Cache persists the results of the links verification at a JSON file keyed by the link, so the next executions only
+// verify the links with expired results. The expiration is defined per provider at 'TTL', keyed by the provider name,
+// and the results from providers without a TTL are not cached. The link is the key, so the cache is meant for the
+// providers with absolute links, like web and github.
+type Cache struct {
+	Path string
+	TTL  map[string]TTL
+
+	mutex   sync.Mutex
+	entries map[string]cacheEntry
+	now     func() time.Time
+}
+
+// Init the internal state and load the cache file if it exists.
+func (c *Cache) Init() error {
+	if c.Path == "" {
+		return errors.New("missing 'path'")
+	}
+	if c.now == nil {
+		c.now = time.Now
+	}
+	c.entries = make(map[string]cacheEntry)
+
+	payload, err := ioutil.ReadFile(c.Path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("fail to read the cache file: %w", err)
+	}
+
+	var file cacheFile
+	if err := json.Unmarshal(payload, &file); err != nil {
+		return fmt.Errorf("fail to decode the cache file: %w", err)
+	}
+	if file.Version != cacheVersion {
+		return nil
+	}
+	for link, entry := range file.Entries {
+		c.entries[link] = entry
+	}
+	return nil
+}
+
+// Get the result of the link if it's cached and not expired.
+func (c *Cache) Get(provider, link string) (service.Result, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry, ok := c.entries[link]
+	if !ok || (entry.Provider != provider) || c.expired(entry) {
+		return service.Result{}, false
+	}
+
+	var redirects []service.Redirect
+	for _, redirect := range entry.Redirects {
+		redirects = append(redirects, service.Redirect{Link: redirect.Link, StatusCode: redirect.StatusCode})
+	}
+	return service.Result{
+		Valid:      entry.Valid,
+		Severity:   service.Severity(entry.Severity),
+		Reason:     service.Reason(entry.Reason),
+		StatusCode: entry.StatusCode,
+		Message:    entry.Message,
+		Redirects:  redirects,
+	}, true
+}
+
+// Set the result of the link. Errored results are not cached.
+func (c *Cache) Set(provider, link string, result service.Result) {
+	if result.Errored || (c.ttl(provider, result.Valid) <= 0) {
+		return
+	}
+
+	var redirects []cacheRedirect
+	for _, redirect := range result.Redirects {
+		redirects = append(redirects, cacheRedirect{Link: redirect.Link, StatusCode: redirect.StatusCode})
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.entries[link] = cacheEntry{
+		Provider:   provider,
+		Valid:      result.Valid,
+		Severity:   string(result.Severity),
+		Reason:     string(result.Reason),
+		StatusCode: result.StatusCode,
+		Message:    result.Message,
+		Redirects:  redirects,
+		CheckedAt:  c.now().UTC(),
+	}
+}
+
+// Save the cache file. The expired entries are discarded.
+func (c *Cache) Save() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	file := cacheFile{Version: cacheVersion, Entries: make(map[string]cacheEntry, len(c.entries))}
+	for link, entry := range c.entries {
+		if !c.expired(entry) {
+			file.Entries[link] = entry
+		}
+	}
+
+	payload, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("fail to encode the cache file: %w", err)
+	}
+
+	// The file is written at a temporary location and then renamed to avoid a partial cache file in case of failure.
+	tmpFile, err := ioutil.TempFile(filepath.Dir(c.Path), filepath.Base(c.Path)+".*")
+	if err != nil {
+		return fmt.Errorf("fail to create the temporary cache file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(payload); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("fail to write the cache file: %w", err)
+	}
+	// The temporary file is only readable by the owner, the cache file is readable by everyone like the baseline.
+	if err := tmpFile.Chmod(0644); err != nil { // nolint: gosec
+		tmpFile.Close()
+		return fmt.Errorf("fail to change the cache file mode: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("fail to close the cache file: %w", err)
+	}
+	if err := os.Rename(tmpFile.Name(), c.Path); err != nil {
+		return fmt.Errorf("fail to move the cache file: %w", err)
+	}
+	return nil
+}
+
+func (c *Cache) expired(entry cacheEntry) bool {
+	ttl := c.ttl(entry.Provider, entry.Valid)
+	return (ttl <= 0) || (c.now().Sub(entry.CheckedAt) > ttl)
+}
+
+func (c *Cache) ttl(provider string, valid bool) time.Duration {
+	ttl := c.TTL[provider]
+	if valid {
+		return ttl.Valid
+	}
+	return ttl.Invalid
+}
@@ -0,0 +1,178 @@
+package cache
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"nitro/markdown-link-check/internal/service"
+)
+
+func TestCacheInit(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "cache")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	invalidPath := filepath.Join(dir, "invalid.json")
+	require.NoError(t, ioutil.WriteFile(invalidPath, []byte("{"), 0600))
+
+	tests := []struct {
+		message   string
+		path      string
+		shouldErr bool
+	}{
+		{
+			message:   "have an error because of a missing path",
+			path:      "",
+			shouldErr: true,
+		},
+		{
+			message:   "have an error because of an invalid cache file",
+			path:      invalidPath,
+			shouldErr: true,
+		},
+		{
+			message:   "initialize without a cache file",
+			path:      filepath.Join(dir, "missing.json"),
+			shouldErr: false,
+		},
+	}
+
+	for i := 0; i < len(tests); i++ {
+		tt := tests[i]
+		t.Run("Should "+tt.message, func(t *testing.T) {
+			cache := Cache{Path: tt.path}
+			require.Equal(t, tt.shouldErr, (cache.Init() != nil))
+		})
+	}
+}
+
+func TestCacheGet(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	ttl := map[string]TTL{"web": {Valid: time.Hour, Invalid: time.Minute}}
+
+	tests := []struct {
+		message  string
+		elapsed  time.Duration
+		provider string
+		result   service.Result
+		expected bool
+	}{
+		{
+			message:  "find a valid result",
+			elapsed:  30 * time.Minute,
+			provider: "web",
+			result:   service.Result{Valid: true, StatusCode: 200},
+			expected: true,
+		},
+		{
+			message:  "not find an expired valid result",
+			elapsed:  2 * time.Hour,
+			provider: "web",
+			result:   service.Result{Valid: true, StatusCode: 200},
+			expected: false,
+		},
+		{
+			message:  "find an invalid result",
+			elapsed:  30 * time.Second,
+			provider: "web",
+			result:   service.Result{Severity: service.SeverityError, Reason: service.ReasonNotFound, StatusCode: 404},
+			expected: true,
+		},
+		{
+			message:  "not find an expired invalid result",
+			elapsed:  30 * time.Minute,
+			provider: "web",
+			result:   service.Result{Severity: service.SeverityError, Reason: service.ReasonNotFound, StatusCode: 404},
+			expected: false,
+		},
+		{
+			message:  "not find a result from a provider without TTL",
+			provider: "file",
+			result:   service.Result{Valid: true},
+			expected: false,
+		},
+		{
+			message:  "not find an errored result",
+			provider: "web",
+			result:   service.Result{Errored: true, Severity: service.SeverityError, Reason: service.ReasonErrored},
+			expected: false,
+		},
+	}
+
+	for i := 0; i < len(tests); i++ {
+		tt := tests[i]
+		t.Run("Should "+tt.message, func(t *testing.T) {
+			t.Parallel()
+
+			dir, err := ioutil.TempDir("", "cache")
+			require.NoError(t, err)
+			defer os.RemoveAll(dir)
+
+			current := now
+			cache := Cache{Path: filepath.Join(dir, "cache.json"), TTL: ttl, now: func() time.Time { return current }}
+			require.NoError(t, cache.Init())
+			cache.Set(tt.provider, "https://website.com", tt.result)
+			current = now.Add(tt.elapsed)
+
+			result, ok := cache.Get(tt.provider, "https://website.com")
+			require.Equal(t, tt.expected, ok)
+			if ok {
+				require.Equal(t, tt.result, result)
+			}
+		})
+	}
+}
+
+func TestCacheSave(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "cache")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var (
+		now   = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+		path  = filepath.Join(dir, "cache.json")
+		ttl   = map[string]TTL{"web": {Valid: time.Hour, Invalid: time.Minute}}
+		valid = service.Result{
+			Valid:      true,
+			Severity:   service.SeverityWarning,
+			Reason:     service.ReasonPermanentRedirect,
+			StatusCode: 200,
+			Redirects:  []service.Redirect{{Link: "https://website.com/moved", StatusCode: 301}},
+		}
+	)
+
+	cache := Cache{Path: path, TTL: ttl, now: func() time.Time { return now }}
+	require.NoError(t, cache.Init())
+	cache.Set("web", "https://website.com/valid", valid)
+	cache.Set("web", "https://website.com/invalid", service.Result{Severity: service.SeverityError, StatusCode: 404})
+	require.NoError(t, cache.Save())
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0644), info.Mode().Perm())
+
+	reloaded := Cache{Path: path, TTL: ttl, now: func() time.Time { return now.Add(30 * time.Minute) }}
+	require.NoError(t, reloaded.Init())
+
+	result, ok := reloaded.Get("web", "https://website.com/valid")
+	require.True(t, ok)
+	require.Equal(t, valid, result)
+
+	_, ok = reloaded.Get("web", "https://website.com/invalid")
+	require.False(t, ok)
+
+	require.NoError(t, reloaded.Save())
+	require.NoError(t, reloaded.Init())
+	require.Len(t, reloaded.entries, 1)
+}
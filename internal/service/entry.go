@@ -1,8 +1,95 @@
 package service
 
-// Entry represents the link present at a given file.
+import "time"
+
+// Reason categorizes why a link is invalid.
+type Reason string
+
+// Reasons reported by the providers.
+const (
+	ReasonNotFound      Reason = "not-found"
+	ReasonAnchorMissing Reason = "anchor-missing"
+	ReasonTimeout       Reason = "timeout"
+	ReasonForbidden     Reason = "forbidden"
+	ReasonStatusCode    Reason = "status-code"
+	ReasonRedirect      Reason = "redirect"
+	ReasonNetwork       Reason = "network"
+	ReasonMalformed     Reason = "malformed"
+	ReasonUnknownDomain Reason = "unknown-domain"
+	ReasonErrored       Reason = "errored"
+)
+
+// Reasons reported by the providers for the valid links that need attention.
+const (
+	ReasonPermanentRedirect Reason = "permanent-redirect"
+)
+
+// Reasons reported by the scan for the reference-style links.
+const (
+	ReasonUndefinedReference Reason = "undefined-reference"
+	ReasonUnusedDefinition   Reason = "unused-definition"
+)
+
+// Kind of the referenced resource.
+type Kind string
+
+// Kinds of the resources referenced at the Markdown files.
+const (
+	KindLink  Kind = "link"
+	KindImage Kind = "image"
+	KindMedia Kind = "media"
+)
+
+// Severity defines how an entry affects the execution.
+type Severity string
+
+// Severities of the entries that need attention. Entries with the error severity fail the execution.
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Redirect is a hop at the redirect chain followed to verify the link. 'Link' is the location redirected to and
+// 'StatusCode' is the status code of the redirect.
+type Redirect struct {
+	Link       string
+	StatusCode int
+}
+
+// Result holds the outcome of the link verification. 'Reason', 'StatusCode' and 'Message' are filled by the providers
+// when the link is invalid, 'Provider' and 'Duration' are filled by the worker. 'Redirects' has the redirect chain
+// followed, in order, when the provider supports it.
+//
+// 'Errored' is set when the link could not be verified at all, in this case 'Valid' is false and 'Message' has the
+// error. The 'Severity' is empty for the entries that don't need attention. 'Baseline' is set when the entry is a
+// known failure accepted by the baseline.
+type Result struct {
+	Valid      bool
+	Errored    bool
+	Baseline   bool
+	Severity   Severity
+	Provider   string
+	Reason     Reason
+	StatusCode int
+	Message    string
+	Redirects  []Redirect
+	Duration   time.Duration
+}
+
+// Failed returns true if the result should fail the execution.
+func (r Result) Failed() bool {
+	return (r.Severity == SeverityError) && !r.Baseline
+}
+
+// Entry represents the link present at a given file. 'Line' and 'Column' start at 1 and are zero when the position
+// is unknown. 'Kind' is the kind of the resource referenced, an empty kind is a link. Entries with the 'Provider'
+// already set are findings from the scan and they don't need to be verified.
 type Entry struct {
-	Path  string
-	Link  string
-	Valid bool
+	Path   string
+	Link   string
+	Kind   Kind
+	Text   string
+	Line   int
+	Column int
+	Result
 }
@@ -0,0 +1,202 @@
+package fix
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"unicode/utf8"
+
+	"nitro/markdown-link-check/internal/service"
+	"nitro/markdown-link-check/internal/service/parser"
+)
+
+// fixDelimiters precede the destinations at the Markdown and HTML links.
+var fixDelimiters = []string{"](", "<", `="`, `='`} // nolint: gochecknoglobals
+
+// Fix rewrites the permanently redirected links at the Markdown files to the final location of the redirect chain.
+// The links are replaced at their position, so the rest of the file is untouched. Only the destinations of the links
+// outside of the code are replaced, the ones that are not written at the source as they were extracted, like the ones
+// with escaped characters, are left as they are. The reference-style links are rewritten at their definitions.
+type Fix struct{}
+
+type fixReplacement struct {
+	line   int
+	offset int
+	link   string
+	target string
+}
+
+// Apply rewrites the links and returns the entries with the rewritten ones updated. The rewritten entries are still
+// reported, with a message pointing to the new link, so the changes can be reviewed.
+func (f Fix) Apply(entries []service.Entry) ([]service.Entry, error) {
+	var (
+		paths   []string
+		indexes = make(map[string][]int)
+	)
+	for i, entry := range entries {
+		if !f.fixable(entry) {
+			continue
+		}
+		if _, ok := indexes[entry.Path]; !ok {
+			paths = append(paths, entry.Path)
+		}
+		indexes[entry.Path] = append(indexes[entry.Path], i)
+	}
+
+	result := make([]service.Entry, len(entries))
+	copy(result, entries)
+	for _, path := range paths {
+		rewritten, err := f.rewrite(path, entries, indexes[path])
+		if err != nil {
+			return nil, fmt.Errorf("fail to rewrite the file '%s': %w", path, err)
+		}
+		for _, index := range rewritten {
+			result[index].Message = fmt.Sprintf("rewritten to '%s'", f.target(result[index]))
+		}
+	}
+	return result, nil
+}
+
+// fixable checks if the entry is a permanently redirected link with a known position.
+func (Fix) fixable(entry service.Entry) bool {
+	return entry.Valid && (entry.Reason == service.ReasonPermanentRedirect) && (len(entry.Redirects) > 0) &&
+		(entry.Line > 0) && (entry.Column > 0)
+}
+
+func (Fix) target(entry service.Entry) string {
+	return entry.Redirects[len(entry.Redirects)-1].Link
+}
+
+// rewrite replaces the links of the entries at the file and returns the indexes of the entries rewritten. Entries
+// with the same destination position, like the usages of the same reference definition, are rewritten once.
+func (f Fix) rewrite(path string, entries []service.Entry, indexes []int) ([]int, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("fail to stat the file: %w", err)
+	}
+	payload, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("fail to read the file: %w", err)
+	}
+	var (
+		document    = parser.NewDocument(payload)
+		lines       = bytes.SplitAfter(payload, []byte("\n"))
+		masked      = bytes.SplitAfter(document.Code(), []byte("\n"))
+		definitions = document.Definitions()
+	)
+
+	var (
+		replacements []fixReplacement
+		rewritten    []int
+		positions    = make(map[[2]int]struct{})
+	)
+	for _, index := range indexes {
+		entry := entries[index]
+		candidates := f.candidates(lines, masked, entry, definitions)
+		if len(candidates) == 0 {
+			continue
+		}
+		rewritten = append(rewritten, index)
+
+		for _, candidate := range candidates {
+			if _, ok := positions[candidate]; ok {
+				continue
+			}
+			positions[candidate] = struct{}{}
+			replacements = append(replacements, fixReplacement{
+				line:   candidate[0],
+				offset: candidate[1],
+				link:   entry.Link,
+				target: f.target(entry),
+			})
+		}
+	}
+	if len(replacements) == 0 {
+		return nil, nil
+	}
+
+	// The replacements are applied from the end of the line, so the offsets of the remaining ones don't change.
+	sort.Slice(replacements, func(i, j int) bool {
+		if replacements[i].line != replacements[j].line {
+			return replacements[i].line < replacements[j].line
+		}
+		return replacements[i].offset > replacements[j].offset
+	})
+	for _, replacement := range replacements {
+		line := lines[replacement.line]
+		var buf bytes.Buffer
+		buf.Write(line[:replacement.offset])
+		buf.WriteString(replacement.target)
+		buf.Write(line[replacement.offset+len(replacement.link):])
+		lines[replacement.line] = buf.Bytes()
+	}
+
+	if err := ioutil.WriteFile(path, bytes.Join(lines, nil), info.Mode().Perm()); err != nil {
+		return nil, fmt.Errorf("fail to write the file: %w", err)
+	}
+	return rewritten, nil
+}
+
+// candidates returns the positions, as line index and byte offset, where the link of the entry is replaced. It's the
+// position of the entry when the link is there, outside of the code, otherwise the entry is assumed to be the usage
+// of a reference-style link and the definitions with the link as destination are returned.
+func (f Fix) candidates(lines, masked [][]byte, entry service.Entry, definitions []parser.Link) [][2]int {
+	if position, ok := f.position(lines, masked, entry.Line, entry.Column, entry.Link); ok {
+		return [][2]int{position}
+	}
+
+	var result [][2]int
+	for _, definition := range definitions {
+		if definition.Destination != entry.Link {
+			continue
+		}
+		if position, ok := f.position(lines, masked, definition.Line, definition.Column, entry.Link); ok {
+			result = append(result, position)
+		}
+	}
+	return result
+}
+
+// position returns the line index and the byte offset of the link when it's at the given position, outside of the
+// code, and preceded by a link delimiter. The code is blanked at the masked lines, where the byte offsets may differ
+// because of the unicode characters, but the columns are the same.
+func (f Fix) position(lines, masked [][]byte, line, column int, link string) ([2]int, bool) {
+	if (line <= 0) || (line > len(lines)) || (line > len(masked)) {
+		return [2]int{}, false
+	}
+	offset, ok := f.offset(lines[line-1], column)
+	if !ok || !bytes.HasPrefix(lines[line-1][offset:], []byte(link)) || !f.delimited(lines[line-1][:offset]) {
+		return [2]int{}, false
+	}
+	maskedOffset, ok := f.offset(masked[line-1], column)
+	if !ok || !bytes.HasPrefix(masked[line-1][maskedOffset:], []byte(link)) {
+		return [2]int{}, false
+	}
+	return [2]int{line - 1, offset}, true
+}
+
+// delimited checks if the prefix ends with a link delimiter or with the colon of a reference definition.
+func (Fix) delimited(prefix []byte) bool {
+	for _, delimiter := range fixDelimiters {
+		if bytes.HasSuffix(prefix, []byte(delimiter)) {
+			return true
+		}
+	}
+	trimmed := bytes.TrimRight(prefix, " \t")
+	return (len(trimmed) < len(prefix)) && bytes.HasSuffix(trimmed, []byte("]:"))
+}
+
+// offset returns the byte offset of the column, which is based on characters and starts at 1.
+func (Fix) offset(line []byte, column int) (int, bool) {
+	var offset int
+	for i := 1; i < column; i++ {
+		if offset >= len(line) {
+			return 0, false
+		}
+		_, size := utf8.DecodeRune(line[offset:])
+		offset += size
+	}
+	return offset, offset <= len(line)
+}
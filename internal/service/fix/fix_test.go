@@ -0,0 +1,130 @@
+package fix
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"nitro/markdown-link-check/internal/service"
+)
+
+func TestFixApply(t *testing.T) {
+	t.Parallel()
+
+	redirected := func(path, link string, line, column int, target string) service.Entry {
+		return service.Entry{
+			Path:   path,
+			Link:   link,
+			Line:   line,
+			Column: column,
+			Result: service.Result{
+				Valid:     true,
+				Severity:  service.SeverityWarning,
+				Provider:  "web",
+				Reason:    service.ReasonPermanentRedirect,
+				Message:   "permanently redirected",
+				Redirects: []service.Redirect{{Link: target, StatusCode: 301}},
+			},
+		}
+	}
+
+	tests := []struct {
+		message  string
+		payload  string
+		entries  func(path string) []service.Entry
+		expected string
+		messages []string
+	}{
+		{
+			message: "rewrite the links at their position and keep the formatting",
+			payload: "# Título\n\nThe [old](https://old.com) and  **[old](https://old.com)**, " +
+				"[other](https://old.com/other).\n\n[ref]: <https://old.com>  \"Title\"\n",
+			entries: func(path string) []service.Entry {
+				return []service.Entry{
+					redirected(path, "https://old.com", 3, 11, "https://new.com/"),
+					redirected(path, "https://old.com", 3, 41, "https://new.com/"),
+					redirected(path, "https://old.com/other", 3, 69, "https://new.com/other#section"),
+					redirected(path, "https://old.com", 5, 9, "https://new.com/"),
+					redirected(path, "https://old.com", 5, 9, "https://new.com/"),
+				}
+			},
+			expected: "# Título\n\nThe [old](https://new.com/) and  **[old](https://new.com/)**, " +
+				"[other](https://new.com/other#section).\n\n[ref]: <https://new.com/>  \"Title\"\n",
+			messages: []string{
+				"rewritten to 'https://new.com/'",
+				"rewritten to 'https://new.com/'",
+				"rewritten to 'https://new.com/other#section'",
+				"rewritten to 'https://new.com/'",
+				"rewritten to 'https://new.com/'",
+			},
+		},
+		{
+			message: "ignore the links that don't match the source and the other entries",
+			payload: "Ünïcödé [old](https://old.com/a\\_b) and <https://old.com>.\n",
+			entries: func(path string) []service.Entry {
+				return []service.Entry{
+					redirected(path, "https://old.com/a_b", 1, 15, "https://new.com/a_b"),
+					redirected(path, "https://old.com", 1, 42, "https://new.com"),
+					{Path: path, Link: "https://old.com", Line: 1, Column: 41, Result: service.Result{Valid: true}},
+				}
+			},
+			expected: "Ünïcödé [old](https://old.com/a\\_b) and <https://new.com>.\n",
+			messages: []string{"permanently redirected", "rewritten to 'https://new.com'", ""},
+		},
+		{
+			message: "ignore the links at the code and at the plain text",
+			payload: "```\n[docs](https://old.com)\n```\n\nThe `Ünï` [a](https://old.com) and https://old.com.\n",
+			entries: func(path string) []service.Entry {
+				return []service.Entry{
+					redirected(path, "https://old.com", 2, 8, "https://new.com"),
+					redirected(path, "https://old.com", 5, 15, "https://new.com"),
+					redirected(path, "https://old.com", 5, 36, "https://new.com"),
+				}
+			},
+			expected: "```\n[docs](https://old.com)\n```\n\nThe `Ünï` [a](https://new.com) and https://old.com.\n",
+			messages: []string{"permanently redirected", "rewritten to 'https://new.com'", "permanently redirected"},
+		},
+		{
+			message: "rewrite the reference links at their definitions",
+			payload: "See [docs] and [more][docs].\n\n```\n[docs]: https://old.com\n```\n\n[docs]: https://old.com\n",
+			entries: func(path string) []service.Entry {
+				return []service.Entry{
+					redirected(path, "https://old.com", 1, 5, "https://new.com"),
+					redirected(path, "https://old.com", 1, 16, "https://new.com"),
+				}
+			},
+			expected: "See [docs] and [more][docs].\n\n```\n[docs]: https://old.com\n```\n\n[docs]: https://new.com\n",
+			messages: []string{"rewritten to 'https://new.com'", "rewritten to 'https://new.com'"},
+		},
+	}
+
+	for i := 0; i < len(tests); i++ {
+		tt := tests[i]
+		t.Run("Should "+tt.message, func(t *testing.T) {
+			t.Parallel()
+
+			dir, err := ioutil.TempDir("", "fix")
+			require.NoError(t, err)
+			defer os.RemoveAll(dir)
+
+			path := filepath.Join(dir, "file.md")
+			require.NoError(t, ioutil.WriteFile(path, []byte(tt.payload), 0600))
+
+			entries, err := Fix{}.Apply(tt.entries(path))
+			require.NoError(t, err)
+
+			payload, err := ioutil.ReadFile(path)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, string(payload))
+
+			messages := make([]string, 0, len(entries))
+			for _, entry := range entries {
+				messages = append(messages, entry.Message)
+			}
+			require.Equal(t, tt.messages, messages)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Status of a changed file.
+type Status string
+
+// Status available.
+const (
+	StatusAdded    Status = "added"
+	StatusModified Status = "modified"
+	StatusDeleted  Status = "deleted"
+)
+
+// Change represents a file changed at the repository. 'Path' is absolute and has the symbolic links resolved, like the
+// repository root reported by git.
+type Change struct {
+	Path   string
+	Status Status
+}
+
+// Git reads the changes from the local repository that contains 'Path'. Only plumbing commands are used and the
+// remote is never contacted, so the reference must be available locally.
+type Git struct {
+	Path string
+}
+
+// Changes returns the files changed at the working tree since the common ancestor between the reference and 'HEAD',
+// the same changes a pull request against the reference would have. Renames are reported as a deletion and an
+// addition, and the untracked files that are not ignored are reported as added. The index is never refreshed, so a
+// file with only its stat information changed is reported as modified. The changes are sorted by path.
+func (g Git) Changes(ctx context.Context, ref string) ([]Change, error) {
+	if g.Path == "" {
+		return nil, errors.New("missing 'path'")
+	}
+	if ref == "" {
+		return nil, errors.New("missing 'ref'")
+	}
+
+	root, err := g.Root(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fail to find the repository root: %w", err)
+	}
+
+	base, err := g.run(ctx, root, "merge-base", ref, "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("fail to find the common ancestor with '%s': %w", ref, err)
+	}
+
+	output, err := g.run(ctx, root, "diff-index", "--name-status", "--no-renames", "-z", strings.TrimSpace(base))
+	if err != nil {
+		return nil, fmt.Errorf("fail to list the changes: %w", err)
+	}
+	changes, err := g.parse(root, output)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err = g.run(ctx, root, "ls-files", "--others", "--exclude-standard", "-z")
+	if err != nil {
+		return nil, fmt.Errorf("fail to list the untracked files: %w", err)
+	}
+	for _, path := range strings.Split(strings.TrimSuffix(output, "\x00"), "\x00") {
+		if path != "" {
+			changes = append(changes, Change{Path: filepath.Join(root, filepath.FromSlash(path)), Status: StatusAdded})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes, nil
+}
+
+// Root returns the absolute path of the repository root.
+func (g Git) Root(ctx context.Context) (string, error) {
+	if g.Path == "" {
+		return "", errors.New("missing 'path'")
+	}
+	root, err := g.run(ctx, g.Path, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(root), nil
+}
+
+// parse the output of 'diff-index --name-status -z', it's a sequence of status and path pairs separated by NUL.
+func (Git) parse(root, output string) ([]Change, error) {
+	fields := strings.Split(strings.TrimSuffix(output, "\x00"), "\x00")
+	if (len(fields) == 1) && (fields[0] == "") {
+		return nil, nil
+	}
+	if len(fields)%2 != 0 {
+		return nil, fmt.Errorf("unexpected diff output '%s'", output)
+	}
+
+	changes := make([]Change, 0, len(fields)/2)
+	for i := 0; i < len(fields); i += 2 {
+		var status Status
+		switch fields[i] {
+		case "A":
+			status = StatusAdded
+		case "D":
+			status = StatusDeleted
+		case "M", "T":
+			status = StatusModified
+		default:
+			continue
+		}
+		changes = append(changes, Change{Path: filepath.Join(root, filepath.FromSlash(fields[i+1])), Status: status})
+	}
+	return changes, nil
+}
+
+func (Git) run(ctx context.Context, dir string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf(
+			"fail to execute 'git %s' (%s): %w", strings.Join(args, " "), strings.TrimSpace(stderr.String()), err,
+		)
+	}
+	return stdout.String(), nil
+}
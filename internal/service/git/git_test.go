@@ -0,0 +1,120 @@
+package git
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitChanges(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	dir, err := ioutil.TempDir("", "git")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	dir, err = filepath.EvalSymlinks(dir)
+	require.NoError(t, err)
+
+	run := func(args ...string) {
+		args = append([]string{"-c", "user.name=test", "-c", "user.email=test@test.com"}, args...)
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+	write := func(path, content string) {
+		path = filepath.Join(dir, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+	}
+
+	run("init", "-q")
+	write("README.md", "# README")
+	write("docs/modified.md", "# Modified")
+	write("docs/deleted.md", "# Deleted")
+	write("docs/renamed.md", "# Renamed")
+	run("add", "-A")
+	run("commit", "-q", "-m", "initial")
+	run("tag", "base")
+
+	write("docs/modified.md", "# Modified again")
+	write("docs/added.md", "# Added")
+	require.NoError(t, os.Remove(filepath.Join(dir, "docs/deleted.md")))
+	run("mv", "docs/renamed.md", "docs/moved.md")
+	run("add", "-A")
+	run("commit", "-q", "-m", "change")
+	write(".gitignore", "*.log")
+	write("docs/untracked.md", "# Untracked")
+	write("docs/ignored.log", "ignored")
+
+	tests := []struct {
+		message   string
+		git       Git
+		ref       string
+		expected  []Change
+		shouldErr bool
+	}{
+		{
+			message:   "have an error because of a missing path",
+			git:       Git{},
+			ref:       "base",
+			shouldErr: true,
+		},
+		{
+			message:   "have an error because of an unknown reference",
+			git:       Git{Path: dir},
+			ref:       "unknown",
+			shouldErr: true,
+		},
+		{
+			message: "list the changes since the reference",
+			git:     Git{Path: filepath.Join(dir, "docs")},
+			ref:     "base",
+			expected: []Change{
+				{Path: filepath.Join(dir, ".gitignore"), Status: StatusAdded},
+				{Path: filepath.Join(dir, "docs/added.md"), Status: StatusAdded},
+				{Path: filepath.Join(dir, "docs/deleted.md"), Status: StatusDeleted},
+				{Path: filepath.Join(dir, "docs/modified.md"), Status: StatusModified},
+				{Path: filepath.Join(dir, "docs/moved.md"), Status: StatusAdded},
+				{Path: filepath.Join(dir, "docs/renamed.md"), Status: StatusDeleted},
+				{Path: filepath.Join(dir, "docs/untracked.md"), Status: StatusAdded},
+			},
+			shouldErr: false,
+		},
+		{
+			message: "list only the untracked files when there are no changes",
+			git:     Git{Path: dir},
+			ref:     "HEAD",
+			expected: []Change{
+				{Path: filepath.Join(dir, ".gitignore"), Status: StatusAdded},
+				{Path: filepath.Join(dir, "docs/untracked.md"), Status: StatusAdded},
+			},
+			shouldErr: false,
+		},
+	}
+
+	root, err := Git{Path: filepath.Join(dir, "docs")}.Root(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, dir, root)
+
+	for i := 0; i < len(tests); i++ {
+		tt := tests[i]
+		t.Run("Should "+tt.message, func(t *testing.T) {
+			changes, err := tt.git.Changes(context.Background(), tt.ref)
+			require.Equal(t, tt.shouldErr, (err != nil))
+			if err != nil {
+				return
+			}
+			require.Equal(t, tt.expected, changes)
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package parser
+
+// Document is a Markdown document with the code, the HTML comments and the reference-style links already found. The
+// source is parsed a single time to find them, so the document is shared by the parsers, the references and the
+// directives of the same file.
+type Document struct {
+	payload    []byte
+	code       []byte
+	references referenceIndex
+}
+
+// NewDocument parses the payload to find the code, the HTML comments and the reference-style links.
+func NewDocument(payload []byte) Document {
+	code, comments := maskSegments(payload)
+	return Document{
+		payload:    payload,
+		code:       mask(payload, code),
+		references: references(mask(payload, code, comments)),
+	}
+}
+
+// Payload returns the source of the document.
+func (d Document) Payload() []byte {
+	return d.payload
+}
+
+// Code returns a copy of the source with the content of the code blocks, fenced or indented, and the code spans
+// blanked. The lines and the columns are kept, so the positions at the source don't change.
+func (d Document) Code() []byte {
+	return d.code
+}
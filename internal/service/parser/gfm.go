@@ -0,0 +1,159 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	goldmarkparser "github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+
+	"nitro/markdown-link-check/internal/service"
+)
+
+// GFM expose a parser that transform GitHub Flavored Markdown into HTML. The tables, the autolinks, the footnotes and
+// the strikethrough are supported and the heading IDs are generated like GitHub does, so the links and the anchors
+// match what is rendered at GitHub.
+type GFM struct {
+	markdown goldmark.Markdown
+	policy   bluemonday.Policy
+}
+
+// Init the internal state.
+func (g *GFM) Init() {
+	g.markdown = goldmark.New(
+		goldmark.WithExtensions(extension.GFM, extension.Footnote),
+		goldmark.WithParserOptions(goldmarkparser.WithAutoHeadingID()),
+		goldmark.WithRendererOptions(html.WithUnsafe()),
+	)
+
+	// The heading IDs can have unicode characters, which are not accepted by the default policy.
+	g.policy = *bluemonday.UGCPolicy()
+	g.policy.AllowAttrs("id").OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+}
+
+// Do transform the Markdown into HTML.
+func (g GFM) Do(payload []byte) []byte {
+	var buf bytes.Buffer
+	ctx := goldmarkparser.NewContext(goldmarkparser.WithIDs(&gfmIDs{values: make(map[string]struct{})}))
+	if err := g.markdown.Convert(payload, &buf, goldmarkparser.WithContext(ctx)); err != nil {
+		return nil
+	}
+	return g.policy.SanitizeBytes(buf.Bytes())
+}
+
+// Links extract the links and the images from the Markdown, including the autolinks and the ones present at raw HTML.
+func (g GFM) Links(document Document) []Link {
+	var (
+		payload = document.Payload()
+		links   []Link
+		locator = newLinkLocator(document)
+		add     = func(destination string, kind service.Kind, text string) {
+			if !allowedLink(destination) {
+				return
+			}
+			line, column := locator.locate(destination)
+			links = append(links, Link{Destination: destination, Kind: kind, Text: text, Line: line, Column: column})
+		}
+		// The autolinks are located by the literal, as it's written at the source, which differs from the destination.
+		addAutoLink = func(destination, literal string) {
+			if !allowedLink(destination) {
+				return
+			}
+			line, column := locator.locateLiteral(literal)
+			links = append(links, Link{
+				Destination: destination, Kind: service.KindLink, Text: literal, Line: line, Column: column,
+			})
+		}
+	)
+
+	root := g.markdown.Parser().Parse(text.NewReader(payload))
+	// The walk function never returns an error.
+	_ = ast.Walk(root, func(node ast.Node, entering bool) (ast.WalkStatus, error) { // nolint: errcheck
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch n := node.(type) {
+		case *ast.Link:
+			add(string(n.Destination), service.KindLink, string(n.Text(payload)))
+		case *ast.Image:
+			add(string(n.Destination), service.KindImage, string(n.Text(payload)))
+		case *ast.AutoLink:
+			destination := string(n.URL(payload))
+			if n.AutoLinkType == ast.AutoLinkEmail && !strings.HasPrefix(strings.ToLower(destination), "mailto:") {
+				destination = "mailto:" + destination
+			}
+			addAutoLink(destination, string(n.Label(payload)))
+		case *ast.HTMLBlock:
+			for _, link := range htmlLinks(g.lines(n.Lines(), payload)) {
+				add(link.destination, link.kind, "")
+			}
+		case *ast.RawHTML:
+			for _, link := range htmlLinks(g.lines(n.Segments, payload)) {
+				add(link.destination, link.kind, "")
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+	return links
+}
+
+// SanitizedAnchorName process the anchor like GitHub does.
+func (GFM) SanitizedAnchorName(value string) string {
+	return gfmSlug(value)
+}
+
+func (GFM) lines(segments *text.Segments, payload []byte) []byte {
+	var buf bytes.Buffer
+	for i := 0; i < segments.Len(); i++ {
+		segment := segments.At(i)
+		buf.Write(segment.Value(payload))
+	}
+	return buf.Bytes()
+}
+
+// gfmIDs generates the heading IDs like GitHub does. Duplicated IDs get a numeric suffix.
+type gfmIDs struct {
+	values map[string]struct{}
+}
+
+func (g *gfmIDs) Generate(value []byte, _ ast.NodeKind) []byte {
+	result := gfmSlug(string(value))
+	if _, ok := g.values[result]; !ok {
+		g.values[result] = struct{}{}
+		return []byte(result)
+	}
+	for i := 1; ; i++ {
+		candidate := fmt.Sprintf("%s-%d", result, i)
+		if _, ok := g.values[candidate]; !ok {
+			g.values[candidate] = struct{}{}
+			return []byte(candidate)
+		}
+	}
+}
+
+func (g *gfmIDs) Put(value []byte) {
+	g.values[string(value)] = struct{}{}
+}
+
+// gfmSlug lowercases the value, removes the punctuation and the symbols, except '-' and '_', and replaces the spaces
+// with '-'. The unicode letters and numbers are kept.
+func gfmSlug(value string) string {
+	var result strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(value)) {
+		switch {
+		case r == ' ':
+			result.WriteRune('-')
+		case (r == '-') || (r == '_') || unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.IsMark(r):
+			result.WriteRune(r)
+		}
+	}
+	return result.String()
+}
@@ -0,0 +1,135 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"nitro/markdown-link-check/internal/service"
+)
+
+func TestGFMDo(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		message  string
+		payload  string
+		expected string
+	}{
+		{
+			message: "generate the heading IDs like GitHub",
+			payload: "# Título **bold** `code`\n\n## Título bold code\n\n### What's new?",
+			expected: `<h1 id="título-bold-code">Título <strong>bold</strong> <code>code</code></h1>` + "\n" +
+				`<h2 id="título-bold-code-1">Título bold code</h2>` + "\n" +
+				`<h3 id="whats-new">What&#39;s new?</h3>` + "\n",
+		},
+		{
+			message: "render the tables and the strikethrough",
+			payload: "| a |\n|---|\n| ~~b~~ |",
+			expected: "<table>\n<thead>\n<tr>\n<th>a</th>\n</tr>\n</thead>\n" +
+				"<tbody>\n<tr>\n<td><del>b</del></td>\n</tr>\n</tbody>\n</table>\n",
+		},
+	}
+
+	for i := 0; i < len(tests); i++ {
+		tt := tests[i]
+		t.Run("Should "+tt.message, func(t *testing.T) {
+			t.Parallel()
+
+			var parser GFM
+			parser.Init()
+			require.Equal(t, tt.expected, string(parser.Do([]byte(tt.payload))))
+		})
+	}
+}
+
+func TestGFMLinks(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		message  string
+		payload  string
+		expected []Link
+	}{
+		{
+			message:  "extract nothing from a document without links",
+			payload:  "# Title\n\nSome text.",
+			expected: nil,
+		},
+		{
+			message: "extract the inline and the reference links with the position",
+			payload: "The [first](first.md) and [second][ref].\n\n[ref]: https://second.com#anchor",
+			expected: []Link{
+				{Destination: "first.md", Kind: service.KindLink, Text: "first", Line: 1, Column: 13},
+				{Destination: "https://second.com#anchor", Kind: service.KindLink, Text: "second", Line: 1, Column: 27},
+			},
+		},
+		{
+			message: "extract the position ignoring the same link at the code blocks and the code spans",
+			payload: "```\n[docs](http://old.com)\n```\n\nThe `http://old.com` and [docs](http://old.com) at http://old.com",
+			expected: []Link{
+				{Destination: "http://old.com", Kind: service.KindLink, Text: "docs", Line: 5, Column: 33},
+				{Destination: "http://old.com", Kind: service.KindLink, Text: "http://old.com", Line: 5, Column: 52},
+			},
+		},
+		{
+			message: "extract the autolinks",
+			payload: "Visit https://website.com, www.website.com or <someone@website.com>.",
+			expected: []Link{
+				{
+					Destination: "https://website.com",
+					Kind:        service.KindLink,
+					Text:        "https://website.com",
+					Line:        1,
+					Column:      7,
+				},
+				{Destination: "http://www.website.com", Kind: service.KindLink, Text: "www.website.com", Line: 1, Column: 28},
+				{
+					Destination: "mailto:someone@website.com",
+					Kind:        service.KindLink,
+					Text:        "someone@website.com",
+					Line:        1,
+					Column:      48,
+				},
+			},
+		},
+		{
+			message: "extract the links from tables and footnotes",
+			payload: "| a |\n|---|\n| [b](b.md) |\n\nNote[^1].\n\n[^1]: [c](c.md)",
+			expected: []Link{
+				{Destination: "b.md", Kind: service.KindLink, Text: "b", Line: 3, Column: 7},
+				{Destination: "c.md", Kind: service.KindLink, Text: "c", Line: 7, Column: 11},
+			},
+		},
+		{
+			message: "extract the images and the links from HTML",
+			payload: "![diagram](arch.png)\n\n<p>\n  <a href=\"block.md\">block</a>\n</p>\n\nInline <img src='logo.png'>.",
+			expected: []Link{
+				{Destination: "arch.png", Kind: service.KindImage, Text: "diagram", Line: 1, Column: 12},
+				{Destination: "block.md", Kind: service.KindLink, Line: 4, Column: 12},
+				{Destination: "logo.png", Kind: service.KindImage, Line: 7, Column: 18},
+			},
+		},
+	}
+
+	for i := 0; i < len(tests); i++ {
+		tt := tests[i]
+		t.Run("Should "+tt.message, func(t *testing.T) {
+			t.Parallel()
+
+			var parser GFM
+			parser.Init()
+			require.Equal(t, tt.expected, parser.Links(NewDocument([]byte(tt.payload))))
+		})
+	}
+}
+
+func TestGFMSanitizedAnchorName(t *testing.T) {
+	t.Parallel()
+
+	var parser GFM
+	parser.Init()
+	require.Equal(t, "título-bold-code", parser.SanitizedAnchorName("Título **bold** `code`"))
+	require.Equal(t, "whats-new-1", parser.SanitizedAnchorName("what's-new-1"))
+	require.Equal(t, "snake_case---dash", parser.SanitizedAnchorName("snake_case - dash"))
+}
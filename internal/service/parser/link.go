@@ -0,0 +1,161 @@
+package parser
+
+import (
+	"bytes"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+
+	"nitro/markdown-link-check/internal/service"
+)
+
+// Link represents a link found at the Markdown document. 'Line' and 'Column' start at 1 and are zero when the position
+// could not be detected. 'Kind' is the kind of the resource referenced, like an image.
+type Link struct {
+	Destination string
+	Kind        service.Kind
+	Text        string
+	Line        int
+	Column      int
+}
+
+// linkDelimiters precede the destinations at the Markdown and HTML links.
+var linkDelimiters = []string{"](", "(<", "<", `"`, "'"} // nolint: gochecknoglobals
+
+// linkLocator finds the position of the links at the source document. The parsers don't keep track of the position of
+// the nodes, so the destinations are searched, in the same order they appear at the document, at the source with the
+// code and the HTML comments blanked. The reference-style links are located at their usage, which is found by the
+// references scan.
+type linkLocator struct {
+	source []byte
+	usages []referenceUsage
+	cursor int
+}
+
+func newLinkLocator(document Document) linkLocator {
+	return linkLocator{source: document.references.source, usages: document.references.usages}
+}
+
+// locate returns the position of the next link with the destination.
+func (l *linkLocator) locate(destination string) (int, int) {
+	return l.position(destination, linkDelimiters, true)
+}
+
+// locateLiteral returns the position of the next link written as plain text, like the autolinks.
+func (l *linkLocator) locateLiteral(literal string) (int, int) {
+	return l.position(literal, []string{""}, false)
+}
+
+func (l *linkLocator) position(value string, prefixes []string, usages bool) (int, int) {
+	if value == "" {
+		return 0, 0
+	}
+	start, end := l.find(l.cursor, value, prefixes, usages)
+	if start < 0 {
+		start, end = l.find(0, value, prefixes, usages)
+	}
+	if start < 0 {
+		return 0, 0
+	}
+	l.cursor = end
+	return linkPosition(l.source, start)
+}
+
+// find returns the start and the end of the first link with the value after the offset. The value is searched with
+// the prefixes to avoid matching it inside the text of another link, and the usages of the reference-style links
+// with the value as destination are considered when 'usages' is set.
+func (l *linkLocator) find(offset int, value string, prefixes []string, usages bool) (int, int) {
+	start, end := -1, -1
+	for _, prefix := range prefixes {
+		index := bytes.Index(l.source[offset:], []byte(prefix+value))
+		if index < 0 {
+			continue
+		}
+		if index = offset + index + len(prefix); (start < 0) || (index < start) {
+			start, end = index, index+len(value)
+		}
+	}
+	if !usages {
+		return start, end
+	}
+	for _, usage := range l.usages {
+		if (usage.offset < offset) || (usage.destination != value) {
+			continue
+		}
+		if (start < 0) || (usage.offset < start) {
+			start, end = usage.offset, usage.offset+1
+		}
+		break
+	}
+	return start, end
+}
+
+// linkPosition returns the line and the column of the offset at the source. The column is based on characters.
+func linkPosition(source []byte, offset int) (int, int) {
+	line := bytes.Count(source[:offset], []byte("\n")) + 1
+	lineStart := bytes.LastIndexByte(source[:offset], '\n') + 1
+	column := utf8.RuneCount(source[lineStart:offset]) + 1
+	return line, column
+}
+
+// htmlLink is a resource referenced at a HTML fragment.
+type htmlLink struct {
+	destination string
+	kind        service.Kind
+}
+
+// htmlLinkAttributes holds the attributes with references per tag.
+var htmlLinkAttributes = map[string]map[string]service.Kind{ // nolint: gochecknoglobals
+	"a":      {"href": service.KindLink},
+	"img":    {"src": service.KindImage},
+	"video":  {"src": service.KindMedia, "poster": service.KindImage},
+	"audio":  {"src": service.KindMedia},
+	"source": {"src": service.KindMedia},
+}
+
+// htmlLinks extract the links, images and media sources from a HTML fragment.
+func htmlLinks(payload []byte) []htmlLink {
+	var (
+		links     []htmlLink
+		tokenizer = html.NewTokenizer(bytes.NewReader(payload))
+	)
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return links
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			attributes, ok := htmlLinkAttributes[token.Data]
+			if !ok {
+				continue
+			}
+			for _, attr := range token.Attr {
+				if kind, ok := attributes[attr.Key]; ok {
+					links = append(links, htmlLink{destination: attr.Val, kind: kind})
+				}
+			}
+		}
+	}
+}
+
+// allowedLink mimics the sanitizer policy, it only accepts relative links or links with the schemes http, https and
+// mailto.
+func allowedLink(destination string) bool {
+	if strings.TrimSpace(destination) == "" {
+		return false
+	}
+
+	endpoint, err := url.Parse(destination)
+	if err != nil {
+		return false
+	}
+
+	switch strings.ToLower(endpoint.Scheme) {
+	case "", "http", "https", "mailto":
+		return true
+	default:
+		return false
+	}
+}
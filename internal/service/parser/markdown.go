@@ -1,9 +1,13 @@
 package parser
 
 import (
+	"strings"
+
 	"github.com/microcosm-cc/bluemonday"
 	"github.com/russross/blackfriday/v2"
 	"github.com/shurcooL/sanitized_anchor_name"
+
+	"nitro/markdown-link-check/internal/service"
 )
 
 // Markdown expose a parser that transform Markdown into HTML.
@@ -18,10 +22,62 @@ func (m *Markdown) Init() {
 
 // Do transform the Markdown into HTML.
 func (m Markdown) Do(payload []byte) []byte {
-	payload = blackfriday.Run(payload, blackfriday.WithExtensions(blackfriday.AutoHeadingIDs))
+	payload = blackfriday.Run(payload, m.extensions())
 	return m.policy.SanitizeBytes(payload)
 }
 
+// Links extract the links and the images from the Markdown, including the ones present at raw HTML.
+func (m Markdown) Links(document Document) []Link {
+	var (
+		payload = document.Payload()
+		links   []Link
+		locator = newLinkLocator(document)
+		add     = func(destination string, kind service.Kind, text string) {
+			if !allowedLink(destination) {
+				return
+			}
+			line, column := locator.locate(destination)
+			links = append(links, Link{Destination: destination, Kind: kind, Text: text, Line: line, Column: column})
+		}
+	)
+
+	root := blackfriday.New(m.extensions()).Parse(payload)
+	root.Walk(func(node *blackfriday.Node, entering bool) blackfriday.WalkStatus {
+		if !entering {
+			return blackfriday.GoToNext
+		}
+
+		switch node.Type {
+		case blackfriday.Link:
+			add(string(node.LinkData.Destination), service.KindLink, m.text(node))
+		case blackfriday.Image:
+			add(string(node.LinkData.Destination), service.KindImage, m.text(node))
+		case blackfriday.HTMLBlock, blackfriday.HTMLSpan:
+			for _, link := range htmlLinks(node.Literal) {
+				add(link.destination, link.kind, "")
+			}
+		}
+		return blackfriday.GoToNext
+	})
+	return links
+}
+
+func (Markdown) extensions() blackfriday.Option {
+	return blackfriday.WithExtensions(blackfriday.AutoHeadingIDs)
+}
+
+// text concatenates the text of the node children.
+func (Markdown) text(node *blackfriday.Node) string {
+	var text strings.Builder
+	node.Walk(func(child *blackfriday.Node, entering bool) blackfriday.WalkStatus {
+		if entering && ((child.Type == blackfriday.Text) || (child.Type == blackfriday.Code)) {
+			text.Write(child.Literal)
+		}
+		return blackfriday.GoToNext
+	})
+	return text.String()
+}
+
 // SanitizedAnchorName process the anchor.
 func (m Markdown) SanitizedAnchorName(text string) string {
 	return sanitized_anchor_name.Create(text)
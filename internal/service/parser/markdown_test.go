@@ -0,0 +1,112 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"nitro/markdown-link-check/internal/service"
+)
+
+func TestMarkdownLinks(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		message  string
+		payload  string
+		expected []Link
+	}{
+		{
+			message:  "extract nothing from a document without links",
+			payload:  "# Title\n\nSome text.",
+			expected: nil,
+		},
+		{
+			message: "extract the inline links with the position",
+			payload: "# Title\n\nThe [first](first.md) and [second](https://second.com#anchor).",
+			expected: []Link{
+				{Destination: "first.md", Kind: service.KindLink, Text: "first", Line: 3, Column: 13},
+				{Destination: "https://second.com#anchor", Kind: service.KindLink, Text: "second", Line: 3, Column: 36},
+			},
+		},
+		{
+			message: "extract the same link multiple times",
+			payload: "[one](file.md)\n\n[two](file.md)",
+			expected: []Link{
+				{Destination: "file.md", Kind: service.KindLink, Text: "one", Line: 1, Column: 7},
+				{Destination: "file.md", Kind: service.KindLink, Text: "two", Line: 3, Column: 7},
+			},
+		},
+		{
+			message: "extract the link with the destination equal to the text",
+			payload: "[file.md](file.md)",
+			expected: []Link{
+				{Destination: "file.md", Kind: service.KindLink, Text: "file.md", Line: 1, Column: 11},
+			},
+		},
+		{
+			message: "extract the links from HTML",
+			payload: "<p>\n  <a href=\"block.md\">block</a>\n</p>\n\nInline <a href='span.md'>span</a>.",
+			expected: []Link{
+				{Destination: "block.md", Kind: service.KindLink, Line: 2, Column: 12},
+				{Destination: "span.md", Kind: service.KindLink, Line: 5, Column: 17},
+			},
+		},
+		{
+			message: "extract the reference links with the position of the usage",
+			payload: "The [reference][ref].\n\nAgain [ref] and [inline](target.md).\n\n[ref]: target.md",
+			expected: []Link{
+				{Destination: "target.md", Kind: service.KindLink, Text: "reference", Line: 1, Column: 5},
+				{Destination: "target.md", Kind: service.KindLink, Text: "ref", Line: 3, Column: 7},
+				{Destination: "target.md", Kind: service.KindLink, Text: "inline", Line: 3, Column: 26},
+			},
+		},
+		{
+			message: "extract the position ignoring the same link at the code blocks and the code spans",
+			payload: "```\n[docs](http://old.com)\n```\n\n    [docs](http://old.com)\n\n" +
+				"The `[docs](http://old.com)` and <!-- [docs](http://old.com) -->\n\n[docs](http://old.com)",
+			expected: []Link{
+				{Destination: "http://old.com", Kind: service.KindLink, Text: "docs", Line: 9, Column: 8},
+			},
+		},
+		{
+			message: "extract the columns based on characters",
+			payload: "Ünïcödé [link](target.md)",
+			expected: []Link{
+				{Destination: "target.md", Kind: service.KindLink, Text: "link", Line: 1, Column: 16},
+			},
+		},
+		{
+			message: "extract the images and the media sources",
+			payload: "![diagram](img/arch.png)\n\n<img src=\"logo.png\">\n\n" +
+				"<video src=\"demo.mp4\" poster=\"poster.png\">\n  <source src=\"demo.webm\">\n</video>\n\n" +
+				"<audio src=\"sound.mp3\"></audio>\n\n![inline](data:image/png;base64,AAAA)",
+			expected: []Link{
+				{Destination: "img/arch.png", Kind: service.KindImage, Text: "diagram", Line: 1, Column: 12},
+				{Destination: "logo.png", Kind: service.KindImage, Line: 3, Column: 11},
+				{Destination: "demo.mp4", Kind: service.KindMedia, Line: 5, Column: 13},
+				{Destination: "poster.png", Kind: service.KindImage, Line: 5, Column: 31},
+				{Destination: "demo.webm", Kind: service.KindMedia, Line: 6, Column: 16},
+				{Destination: "sound.mp3", Kind: service.KindMedia, Line: 9, Column: 13},
+			},
+		},
+		{
+			message: "ignore the links not allowed by the sanitizer",
+			payload: "[ftp](ftp://server.com) [js](javascript:alert(1)) [mail](mailto:someone@server.com)",
+			expected: []Link{
+				{Destination: "mailto:someone@server.com", Kind: service.KindLink, Text: "mail", Line: 1, Column: 58},
+			},
+		},
+	}
+
+	for i := 0; i < len(tests); i++ {
+		tt := tests[i]
+		t.Run("Should "+tt.message, func(t *testing.T) {
+			t.Parallel()
+
+			var parser Markdown
+			parser.Init()
+			require.Equal(t, tt.expected, parser.Links(NewDocument([]byte(tt.payload))))
+		})
+	}
+}
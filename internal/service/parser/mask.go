@@ -0,0 +1,108 @@
+package parser
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// maskMarkdown is the CommonMark parser used to find the code and the comments at the source.
+var maskMarkdown = goldmark.New() // nolint: gochecknoglobals
+
+// maskSegments returns the segments of the code blocks, fenced or indented, and of the code spans, and the segments
+// of the HTML comments. The code is found at the syntax tree, so the indented code blocks are distinguished from the
+// indented content of the list items.
+func maskSegments(payload []byte) ([]text.Segment, []text.Segment) {
+	var (
+		code     []text.Segment
+		comments []text.Segment
+		lines    = func(segments *[]text.Segment, values *text.Segments) {
+			for i := 0; i < values.Len(); i++ {
+				*segments = append(*segments, values.At(i))
+			}
+		}
+	)
+
+	root := maskMarkdown.Parser().Parse(text.NewReader(payload))
+	// The walk function never returns an error.
+	_ = ast.Walk(root, func(node ast.Node, entering bool) (ast.WalkStatus, error) { // nolint: errcheck
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch n := node.(type) {
+		case *ast.FencedCodeBlock:
+			if n.Info != nil {
+				code = append(code, n.Info.Segment)
+			}
+			lines(&code, n.Lines())
+		case *ast.CodeBlock:
+			lines(&code, n.Lines())
+		case *ast.CodeSpan:
+			for child := n.FirstChild(); child != nil; child = child.NextSibling() {
+				if value, ok := child.(*ast.Text); ok {
+					code = append(code, value.Segment)
+				}
+			}
+			return ast.WalkSkipChildren, nil
+		case *ast.HTMLBlock:
+			if n.HTMLBlockType == ast.HTMLBlockType2 {
+				lines(&comments, n.Lines())
+				if n.HasClosure() {
+					comments = append(comments, n.ClosureLine)
+				}
+			}
+		case *ast.RawHTML:
+			if n.Segments.Len() == 0 {
+				break
+			}
+			if first := n.Segments.At(0); bytes.HasPrefix(first.Value(payload), []byte("<!--")) {
+				lines(&comments, n.Segments)
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+	return code, comments
+}
+
+// mask returns a copy of the payload with the segments blanked. The lines and the columns are kept, so the positions
+// at the payload don't change.
+func mask(payload []byte, segments ...[]text.Segment) []byte {
+	var all []text.Segment
+	for _, values := range segments {
+		all = append(all, values...)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Start < all[j].Start })
+
+	var (
+		result = make([]byte, 0, len(payload))
+		last   int
+	)
+	for _, segment := range all {
+		start := segment.Start
+		if start < last {
+			start = last
+		}
+		if segment.Stop <= start {
+			continue
+		}
+		result = append(result, payload[last:start]...)
+		result = append(result, blank(payload[start:segment.Stop])...)
+		last = segment.Stop
+	}
+	return append(result, payload[last:]...)
+}
+
+// blank replaces the content with spaces, the line breaks are kept. Each character becomes a single space, so the
+// columns, which are based on characters, don't change.
+func blank(payload []byte) []byte {
+	return bytes.Map(func(r rune) rune {
+		if (r == '\n') || (r == '\r') {
+			return r
+		}
+		return ' '
+	}, payload)
+}
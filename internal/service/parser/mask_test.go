@@ -0,0 +1,53 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMask(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		message  string
+		payload  string
+		comments bool
+		expected string
+	}{
+		{
+			message:  "blank the fenced and indented code blocks and the code spans",
+			payload:  "```go\n[a](a.md)\n```\n\n    [b](b.md)\n\nThe `[c](c.md)` and [d](d.md).",
+			expected: "```  \n         \n```\n\n             \n\nThe `         ` and [d](d.md).",
+		},
+		{
+			message:  "keep the HTML comments",
+			payload:  "<!-- [a](a.md) -->\n\nThe <!-- b --> text.",
+			expected: "<!-- [a](a.md) -->\n\nThe <!-- b --> text.",
+		},
+		{
+			message:  "blank the HTML comments",
+			payload:  "<!-- [a](a.md) -->\n\nThe <!-- b --> text.",
+			comments: true,
+			expected: "                  \n\nThe            text.",
+		},
+		{
+			message:  "keep the columns of the unicode characters",
+			payload:  "`Ünïcödé` [a](a.md)",
+			expected: "`       ` [a](a.md)",
+		},
+	}
+
+	for i := 0; i < len(tests); i++ {
+		tt := tests[i]
+		t.Run("Should "+tt.message, func(t *testing.T) {
+			t.Parallel()
+			document := NewDocument([]byte(tt.payload))
+			masked := document.Code()
+			if tt.comments {
+				masked = document.references.source
+			}
+			require.Equal(t, tt.expected, string(masked))
+		})
+	}
+}
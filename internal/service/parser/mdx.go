@@ -0,0 +1,81 @@
+package parser
+
+import (
+	"bytes"
+	"regexp"
+
+	"nitro/markdown-link-check/internal/service"
+)
+
+// nolint: gochecknoglobals
+var (
+	mdxComponentRegex = regexp.MustCompile(`<[A-Z][\w.]*(?:\s[^<>]*)?/?>`)
+	mdxPropRegex      = regexp.MustCompile("\\b(?:href|src)=(?:\"([^\"]*)\"|'([^']*)'|\\{\\s*[\"'`]([^\"'`]*)[\"'`]\\s*\\})")
+)
+
+// MDX prepares a MDX document to be processed as Markdown. The ESM 'import' and 'export' statements are blanked, they
+// would be handled as text otherwise, and the lines are kept so the positions at the document don't change. The
+// links at the 'href' and 'src' props of the JSX components are returned, they're not visible to the Markdown parser.
+// The 'src' props are assumed to be images.
+func MDX(payload []byte) ([]byte, []Link) {
+	var (
+		markdown = make([]byte, 0, len(payload))
+		jsx      = make([]byte, 0, len(payload))
+		fence    []byte
+		esm      bool
+	)
+	for _, line := range bytes.SplitAfter(payload, []byte("\n")) {
+		trimmed := bytes.TrimSpace(line)
+		switch {
+		case fence != nil:
+			if bytes.HasPrefix(trimmed, fence) {
+				fence = nil
+			}
+			markdown = append(markdown, line...)
+			jsx = append(jsx, blank(line)...)
+		case bytes.HasPrefix(trimmed, []byte("```")), bytes.HasPrefix(trimmed, []byte("~~~")):
+			fence = trimmed[:3]
+			markdown = append(markdown, line...)
+			jsx = append(jsx, blank(line)...)
+		case esm && (len(trimmed) == 0):
+			esm = false
+			markdown = append(markdown, line...)
+			jsx = append(jsx, line...)
+		case esm, bytes.HasPrefix(line, []byte("import ")), bytes.HasPrefix(line, []byte("export ")):
+			// The statement goes until the next blank line, like at the MDX specification.
+			esm = true
+			markdown = append(markdown, blank(line)...)
+			jsx = append(jsx, blank(line)...)
+		default:
+			markdown = append(markdown, line...)
+			jsx = append(jsx, line...)
+		}
+	}
+	return markdown, mdxLinks(jsx)
+}
+
+// mdxLinks extracts the links from the JSX components.
+func mdxLinks(payload []byte) []Link {
+	var links []Link
+	for _, component := range mdxComponentRegex.FindAllIndex(payload, -1) {
+		for _, match := range mdxPropRegex.FindAllSubmatchIndex(payload[component[0]:component[1]], -1) {
+			kind := service.KindLink
+			if bytes.HasPrefix(payload[component[0]+match[0]:], []byte("src")) {
+				kind = service.KindImage
+			}
+			for group := 2; group < len(match); group += 2 {
+				if match[group] < 0 {
+					continue
+				}
+				start := component[0] + match[group]
+				destination := string(payload[start : component[0]+match[group+1]])
+				if allowedLink(destination) {
+					line, column := linkPosition(payload, start)
+					links = append(links, Link{Destination: destination, Kind: kind, Line: line, Column: column})
+				}
+				break
+			}
+		}
+	}
+	return links
+}
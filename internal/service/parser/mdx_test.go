@@ -0,0 +1,60 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"nitro/markdown-link-check/internal/service"
+)
+
+func TestMDX(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		message          string
+		payload          string
+		expectedMarkdown string
+		expectedLinks    []Link
+	}{
+		{
+			message:          "keep a document without ESM and JSX",
+			payload:          "# Title\n\n[link](file.md)",
+			expectedMarkdown: "# Title\n\n[link](file.md)",
+		},
+		{
+			message: "blank the import and export statements",
+			payload: "import Card from './card.js'\nexport const meta = {\n  href: 'https://website.com',\n}\n\n" +
+				"# Title",
+			expectedMarkdown: "                            \n                     \n" +
+				"                              \n \n\n# Title",
+		},
+		{
+			message: "extract the links from the JSX components",
+			payload: "# Title\n\n<Card href=\"card.md\" />\n\n<Image\n  src={'image.png'}\n  title=\"Image\"\n/>\n\n" +
+				"<Link href=\"javascript:alert(1)\" />",
+			expectedMarkdown: "# Title\n\n<Card href=\"card.md\" />\n\n<Image\n  src={'image.png'}\n  title=\"Image\"\n/>\n\n" +
+				"<Link href=\"javascript:alert(1)\" />",
+			expectedLinks: []Link{
+				{Destination: "card.md", Kind: service.KindLink, Line: 3, Column: 13},
+				{Destination: "image.png", Kind: service.KindImage, Line: 6, Column: 9},
+			},
+		},
+		{
+			message:          "ignore the code blocks",
+			payload:          "```jsx\nimport Card from './card.js'\n<Card href=\"card.md\" />\n```",
+			expectedMarkdown: "```jsx\nimport Card from './card.js'\n<Card href=\"card.md\" />\n```",
+		},
+	}
+
+	for i := 0; i < len(tests); i++ {
+		tt := tests[i]
+		t.Run("Should "+tt.message, func(t *testing.T) {
+			t.Parallel()
+
+			markdown, links := MDX([]byte(tt.payload))
+			require.Equal(t, tt.expectedMarkdown, string(markdown))
+			require.Equal(t, tt.expectedLinks, links)
+		})
+	}
+}
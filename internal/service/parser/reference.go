@@ -0,0 +1,214 @@
+package parser
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"nitro/markdown-link-check/internal/service"
+)
+
+// nolint: gochecknoglobals
+var (
+	referenceDefinitionRegex = regexp.MustCompile(`(?m)^ {0,3}\[((?:[^\[\]\\\n]|\\.)+)\]:[ \t]*(?:<([^>\n]*)>|(\S+))`)
+)
+
+// Reference is a broken reference-style link. When 'Undefined' is set, it's a reference to a label without a
+// definition and 'Destination' is empty, otherwise it's a definition that is never referenced and 'Destination' is the
+// definition destination. The position is the position of the label.
+type Reference struct {
+	Link
+	Label     string
+	Undefined bool
+}
+
+// referenceDefinition is a definition at the position of the label, 'offset' is the position of the destination.
+type referenceDefinition struct {
+	Link
+	label  string
+	offset int
+	used   bool
+}
+
+// referenceUsage is a reference-style link with a definition, 'offset' is the position of the link at the source.
+type referenceUsage struct {
+	offset      int
+	destination string
+}
+
+// referenceIndex holds the reference-style links found at the source. The source has the code, the HTML comments and
+// the definitions blanked.
+type referenceIndex struct {
+	source      []byte
+	definitions []*referenceDefinition
+	usages      []referenceUsage
+	undefined   []Reference
+}
+
+// References returns the references to labels without a definition and the definitions that are never referenced.
+// They're both discarded by the Markdown parsers, so they're detected at the source. The code blocks, the code spans
+// and the HTML comments are ignored, as the footnotes. Shortcut references, like '[label]', are only considered when
+// there is a definition because otherwise they're regular text, like the task list items.
+func (d Document) References() []Reference {
+	result := append([]Reference(nil), d.references.undefined...)
+	for _, definition := range d.references.definitions {
+		if !definition.used {
+			result = append(result, Reference{Link: definition.Link, Label: definition.label})
+		}
+	}
+	return result
+}
+
+// Definitions returns the definitions of the reference-style links at the position of the destination. The definitions
+// at the code blocks and at the HTML comments are ignored.
+func (d Document) Definitions() []Link {
+	result := make([]Link, 0, len(d.references.definitions))
+	for _, definition := range d.references.definitions {
+		line, column := linkPosition(d.references.source, definition.offset)
+		result = append(result, Link{
+			Destination: definition.Destination, Kind: service.KindLink, Line: line, Column: column,
+		})
+	}
+	return result
+}
+
+// references finds the reference-style links at the source, which has the code and the HTML comments blanked.
+func references(source []byte) referenceIndex {
+	var (
+		index  referenceIndex
+		labels = make(map[string][]*referenceDefinition)
+		masked = make([]byte, 0, len(source))
+		last   int
+	)
+	for _, match := range referenceDefinitionRegex.FindAllSubmatchIndex(source, -1) {
+		label := string(source[match[2]:match[3]])
+		if strings.HasPrefix(label, "^") {
+			continue
+		}
+		start, stop := match[6], match[7]
+		if match[4] >= 0 {
+			start, stop = match[4], match[5]
+		}
+		line, column := linkPosition(source, match[2]-1)
+		definition := &referenceDefinition{
+			Link:   Link{Destination: string(source[start:stop]), Kind: service.KindLink, Line: line, Column: column},
+			label:  label,
+			offset: start,
+		}
+		index.definitions = append(index.definitions, definition)
+		key := referenceNormalize(label)
+		labels[key] = append(labels[key], definition)
+
+		// The definition is blanked until the end of the line to not be handled as a shortcut reference.
+		end := len(source)
+		if i := bytes.IndexByte(source[match[0]:], '\n'); i >= 0 {
+			end = match[0] + i
+		}
+		masked = append(masked, source[last:match[0]]...)
+		masked = append(masked, blank(source[match[0]:end])...)
+		last = end
+	}
+	source = append(masked, source[last:]...)
+	index.source = source
+
+	consumed := make(map[int]struct{})
+	// use marks the definitions of the label as used and records the usage with the first one, which is the one
+	// that is applied.
+	use := func(label string, offset int) bool {
+		matches, ok := labels[referenceNormalize(label)]
+		for _, definition := range matches {
+			definition.used = true
+		}
+		if ok {
+			index.usages = append(index.usages, referenceUsage{offset: offset, destination: matches[0].Destination})
+		}
+		return ok
+	}
+	for i := 0; i < len(source); i++ {
+		if _, ok := consumed[i]; ok || (source[i] != '[') || referenceEscaped(source, i) {
+			continue
+		}
+		end := referenceClosing(source, i)
+		if end < 0 {
+			continue
+		}
+		text := string(source[i+1 : end])
+		if strings.HasPrefix(text, "^") {
+			continue
+		}
+
+		next := end + 1
+		switch {
+		case (next < len(source)) && (source[next] == '('):
+			continue
+		case (next < len(source)) && (source[next] == '['):
+			labelEnd := referenceClosing(source, next)
+			if labelEnd < 0 {
+				use(text, i)
+				continue
+			}
+			consumed[next] = struct{}{}
+
+			label, offset := string(source[next+1:labelEnd]), next
+			if strings.TrimSpace(label) == "" {
+				label, offset = text, i
+			}
+			if use(label, i) {
+				continue
+			}
+
+			kind := service.KindLink
+			if (i > 0) && (source[i-1] == '!') {
+				kind = service.KindImage
+			}
+			line, column := linkPosition(source, offset)
+			index.undefined = append(index.undefined, Reference{
+				Link:      Link{Kind: kind, Text: text, Line: line, Column: column},
+				Label:     label,
+				Undefined: true,
+			})
+		default:
+			use(text, i)
+		}
+	}
+	return index
+}
+
+// referenceClosing returns the position of the bracket that closes the one at 'start', or -1 if there is none before
+// the end of the paragraph.
+func referenceClosing(source []byte, start int) int {
+	depth := 0
+	for i := start; i < len(source); i++ {
+		switch source[i] {
+		case '\\':
+			i++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		case '\n':
+			lineEnd := bytes.IndexByte(source[i+1:], '\n')
+			if (lineEnd >= 0) && (len(bytes.TrimSpace(source[i+1:i+1+lineEnd])) == 0) {
+				return -1
+			}
+		}
+	}
+	return -1
+}
+
+// referenceEscaped checks if the character at the position is escaped by an odd number of backslashes.
+func referenceEscaped(source []byte, position int) bool {
+	var count int
+	for i := position - 1; (i >= 0) && (source[i] == '\\'); i-- {
+		count++
+	}
+	return count%2 == 1
+}
+
+// referenceNormalize normalizes the label to be matched case-insensitively with the whitespaces collapsed.
+func referenceNormalize(label string) string {
+	return strings.ToLower(strings.Join(strings.Fields(label), " "))
+}
@@ -0,0 +1,104 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"nitro/markdown-link-check/internal/service"
+)
+
+func TestReferences(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		message  string
+		payload  string
+		expected []Reference
+	}{
+		{
+			message: "find nothing when all the references are defined and used",
+			payload: "The [full][ref], [collapsed][], [shortcut] and ![image][IMG].\n\n" +
+				"[ref]: a.md\n[collapsed]: b.md\n[Shortcut]: c.md\n[img]: d.png",
+			expected: nil,
+		},
+		{
+			message: "find the references without a definition",
+			payload: "The [full][missing] and [collapsed][].\n\n![image][logo]",
+			expected: []Reference{
+				{
+					Link:      Link{Kind: service.KindLink, Text: "full", Line: 1, Column: 11},
+					Label:     "missing",
+					Undefined: true,
+				},
+				{
+					Link:      Link{Kind: service.KindLink, Text: "collapsed", Line: 1, Column: 25},
+					Label:     "collapsed",
+					Undefined: true,
+				},
+				{
+					Link:      Link{Kind: service.KindImage, Text: "image", Line: 3, Column: 9},
+					Label:     "logo",
+					Undefined: true,
+				},
+			},
+		},
+		{
+			message: "find the definitions that are never used",
+			payload: "The [used] one.\n\n[used]: a.md\n  [unused]: <https://website.com> \"Title\"",
+			expected: []Reference{
+				{
+					Link:  Link{Destination: "https://website.com", Kind: service.KindLink, Line: 4, Column: 3},
+					Label: "unused",
+				},
+			},
+		},
+		{
+			message: "find the references at nested brackets",
+			payload: "[![badge][img]][missing]\n\n[img]: badge.svg",
+			expected: []Reference{
+				{
+					Link:      Link{Kind: service.KindLink, Text: "![badge][img]", Line: 1, Column: 16},
+					Label:     "missing",
+					Undefined: true,
+				},
+			},
+		},
+		{
+			message: "ignore the code, the comments, the footnotes, the inline links and the plain brackets",
+			payload: "```\n[code][missing]\n```\n\n`[span][missing]` <!-- [comment][missing] -->\n\n" +
+				"- [ ] task\n- [x] done\n\nA note[^1] and [inline](a.md) and \\[escaped\\][missing].\n\n[^1]: The note.",
+			expected: nil,
+		},
+		{
+			message: "ignore the indented code blocks but not the indented content of the list items",
+			payload: "Matrix:\n\n    matrix[i][j] = 0\n\n- Item\n\n    With [text][missing].",
+			expected: []Reference{
+				{
+					Link:      Link{Kind: service.KindLink, Text: "text", Line: 7, Column: 16},
+					Label:     "missing",
+					Undefined: true,
+				},
+			},
+		},
+	}
+
+	for i := 0; i < len(tests); i++ {
+		tt := tests[i]
+		t.Run("Should "+tt.message, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.expected, NewDocument([]byte(tt.payload)).References())
+		})
+	}
+}
+
+func TestDefinitions(t *testing.T) {
+	t.Parallel()
+
+	payload := "The [a] and [b].\n\n[a]: a.md\n  [b]: <https://website.com> \"Title\"\n\n```\n[c]: c.md\n```"
+	expected := []Link{
+		{Destination: "a.md", Kind: service.KindLink, Line: 3, Column: 6},
+		{Destination: "https://website.com", Kind: service.KindLink, Line: 4, Column: 9},
+	}
+	require.Equal(t, expected, NewDocument([]byte(payload)).Definitions())
+}
@@ -2,9 +2,12 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"regexp"
+
+	"nitro/markdown-link-check/internal/service"
 )
 
 type emailChecker interface {
@@ -30,23 +33,34 @@ func (e *Email) Init() error {
 	return nil
 }
 
+// Name returns the provider identification.
+func (Email) Name() string {
+	return "email"
+}
+
 // Authority checks if the email provider is responsible to process the entry.
 func (e Email) Authority(uri string) bool {
 	return e.regex.Match([]byte(uri))
 }
 
 // Valid check if the address is valid.
-func (e Email) Valid(ctx context.Context, _, uri string) (bool, error) {
+func (e Email) Valid(ctx context.Context, _, uri string) (service.Result, error) {
 	fragments := e.regex.FindStringSubmatch(uri)
 	if fragments == nil {
-		return false, nil
+		return service.Result{Reason: service.ReasonMalformed, Message: "malformed email address"}, nil
 	}
 
 	exists, err := e.checker.exists(fragments[1])
 	if err != nil {
-		return false, fmt.Errorf("fail to check the MX DNS entries: %w", err)
+		return service.Result{}, fmt.Errorf("fail to check the MX DNS entries: %w", err)
+	}
+	if !exists {
+		return service.Result{
+			Reason:  service.ReasonUnknownDomain,
+			Message: fmt.Sprintf("domain '%s' has no MX DNS entries", fragments[1]),
+		}, nil
 	}
-	return exists, nil
+	return service.Result{Valid: true}, nil
 }
 
 func (e *Email) initRegex() error {
@@ -64,6 +78,10 @@ type emailNetLookupMX struct{}
 func (emailNetLookupMX) exists(domain string) (bool, error) {
 	mxs, err := net.LookupMX(domain)
 	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return false, nil
+		}
 		return false, err
 	}
 	return (len(mxs) > 0), nil
@@ -6,6 +6,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"nitro/markdown-link-check/internal/service"
 )
 
 func TestEmailInit(t *testing.T) {
@@ -71,6 +73,7 @@ func TestEmailValid(t *testing.T) {
 		uri       string
 		isValid   bool
 		shouldErr bool
+		reason    service.Reason
 	}{
 		{
 			message:   "attest the email as valid",
@@ -95,6 +98,7 @@ func TestEmailValid(t *testing.T) {
 			uri:       "something",
 			shouldErr: false,
 			isValid:   false,
+			reason:    service.ReasonMalformed,
 		},
 		{
 			message:   "attest the email as invalid #2",
@@ -103,6 +107,7 @@ func TestEmailValid(t *testing.T) {
 			uri:       "http://gonitro.com",
 			shouldErr: false,
 			isValid:   false,
+			reason:    service.ReasonMalformed,
 		},
 		{
 			message:   "attest the email as invalid #3",
@@ -111,6 +116,16 @@ func TestEmailValid(t *testing.T) {
 			uri:       "unknow@email.com",
 			shouldErr: false,
 			isValid:   false,
+			reason:    service.ReasonMalformed,
+		},
+		{
+			message:   "attest the email as invalid because of an unknown domain",
+			ctx:       context.Background(),
+			checker:   emailCheckerMock{response: false, shouldErr: false},
+			uri:       "mailto:unknow@email.com",
+			shouldErr: false,
+			isValid:   false,
+			reason:    service.ReasonUnknownDomain,
 		},
 		{
 			message:   "attest the email as invalid #4",
@@ -128,12 +143,13 @@ func TestEmailValid(t *testing.T) {
 			client := Email{checker: tt.checker}
 			require.NoError(t, client.Init())
 
-			isValid, err := client.Valid(tt.ctx, "", tt.uri)
+			result, err := client.Valid(tt.ctx, "", tt.uri)
 			require.Equal(t, tt.shouldErr, (err != nil))
 			if err != nil {
 				return
 			}
-			require.Equal(t, tt.isValid, isValid)
+			require.Equal(t, tt.isValid, result.Valid)
+			require.Equal(t, tt.reason, result.Reason)
 		})
 	}
 }
@@ -13,6 +13,9 @@ import (
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
+
+	"nitro/markdown-link-check/internal/service"
+	"nitro/markdown-link-check/internal/service/parser"
 )
 
 type fileReader interface {
@@ -36,10 +39,12 @@ func (fileReaderAPI) readFile(filer string) ([]byte, error) {
 	return ioutil.ReadFile(filer)
 }
 
-// File provider is responsible for checking if the file exists at the filesystem.
+// File provider is responsible for checking if the file exists at the filesystem. The anchors are verified at the
+// files with the 'Extensions', which defaults to '.md'.
 type File struct {
-	Path   string
-	Parser fileParser
+	Path       string
+	Parser     fileParser
+	Extensions []string
 
 	reader      fileReader
 	schemaRegex regexp.Regexp
@@ -59,6 +64,10 @@ func (f *File) Init() error {
 		return errors.New("missing 'parser'")
 	}
 
+	if len(f.Extensions) == 0 {
+		f.Extensions = []string{".md"}
+	}
+
 	if err := f.initRegex(); err != nil {
 		return fmt.Errorf("fail to initialize the regex expressions: %w", err)
 	}
@@ -66,24 +75,49 @@ func (f *File) Init() error {
 	return nil
 }
 
+// Name returns the provider identification.
+func (File) Name() string {
+	return "file"
+}
+
 // Authority checks if the file provider is responsible to process the entry.
 func (f File) Authority(uri string) bool {
 	return f.schemaRegex.Match([]byte(uri))
 }
 
 // Valid check if the link is valid.
-func (f File) Valid(ctx context.Context, filePath, uri string) (bool, error) {
+func (f File) Valid(ctx context.Context, filePath, uri string) (service.Result, error) {
 	if f.isMarkdown(filePath) {
-		found, err := f.checkMarkdown(filePath, uri)
+		result, err := f.checkMarkdown(filePath, uri)
 		if err != nil {
-			return false, fmt.Errorf("fail to check the markdown: %w", err)
+			return service.Result{}, fmt.Errorf("fail to check the markdown: %w", err)
 		}
-		return found, nil
+		return result, nil
 	}
 
 	path := filepath.Join(filepath.Dir(filePath), uri)
-	_, itemExists := f.reader.fileExists(path)
-	return itemExists, nil
+	if _, itemExists := f.reader.fileExists(path); !itemExists {
+		return f.notFound(path), nil
+	}
+	return service.Result{Valid: true}, nil
+}
+
+// Resolve returns the location of the resource referenced by the link, so the same resource referenced from different
+// files has the same location.
+func (f File) Resolve(filePath, uri string) string {
+	parsedURI, err := url.Parse(uri)
+	if (err != nil) || !f.isMarkdown(filePath) {
+		return filepath.Join(filepath.Dir(filePath), uri)
+	}
+
+	path := filePath
+	if parsedURI.Path != "" {
+		path = filepath.Join(filepath.Dir(filePath), parsedURI.Path)
+	}
+	if parsedURI.Fragment == "" {
+		return path
+	}
+	return path + "#" + parsedURI.Fragment
 }
 
 func (f *File) initRegex() error {
@@ -96,15 +130,20 @@ func (f *File) initRegex() error {
 	return nil
 }
 
-func (File) isMarkdown(path string) bool {
-	return filepath.Ext(path) == ".md"
+func (f File) isMarkdown(path string) bool {
+	for _, extension := range f.Extensions {
+		if strings.EqualFold(filepath.Ext(path), extension) {
+			return true
+		}
+	}
+	return false
 }
 
 // checkMarkdown check if the uri is a Markdown, if positive, it will be responsible to detect if the link is valid.
-func (f File) checkMarkdown(path, uri string) (bool, error) {
+func (f File) checkMarkdown(path, uri string) (service.Result, error) {
 	parsedURI, err := url.Parse(uri)
 	if err != nil {
-		return false, fmt.Errorf("fail to parse the uri '%s': %w", uri, err)
+		return service.Result{}, fmt.Errorf("fail to parse the uri '%s': %w", uri, err)
 	}
 
 	// If the link is just a anchor like '#something' it will fit into the first condition. Otherwise it will be something
@@ -120,29 +159,32 @@ func (f File) checkMarkdown(path, uri string) (bool, error) {
 	// If the path is a directory we get a valid response.
 	pathStat, valid := f.reader.fileExists(expandedPath)
 	if !valid {
-		return false, nil
+		return f.notFound(expandedPath), nil
 	}
 	if pathStat.IsDir() {
-		return true, nil
+		return service.Result{Valid: true}, nil
 	}
 
-	if filepath.Ext(expandedPath) != ".md" {
-		return true, nil
+	if !f.isMarkdown(expandedPath) {
+		return service.Result{Valid: true}, nil
 	}
 
 	if parsedURI.Fragment == "" {
-		return true, nil
+		return service.Result{Valid: true}, nil
 	}
 
 	payload, err := f.reader.readFile(expandedPath)
 	if err != nil {
-		return false, fmt.Errorf("fail to read the file '%s': %w", expandedPath, err)
+		return service.Result{}, fmt.Errorf("fail to read the file '%s': %w", expandedPath, err)
+	}
+	if strings.EqualFold(filepath.Ext(expandedPath), ".mdx") {
+		payload, _ = parser.MDX(payload)
 	}
 	payload = f.Parser.Do(payload)
 
 	doc, err := goquery.NewDocumentFromReader(bytes.NewBuffer(payload))
 	if err != nil {
-		return false, fmt.Errorf("fail to parse the HTML: %w", err)
+		return service.Result{}, fmt.Errorf("fail to parse the HTML: %w", err)
 	}
 
 	fragment := f.Parser.SanitizedAnchorName(parsedURI.Fragment)
@@ -152,11 +194,18 @@ func (f File) checkMarkdown(path, uri string) (bool, error) {
 	}
 	for _, h := range handlers {
 		if h(doc, fragment, parsedURI.Fragment) {
-			return true, nil
+			return service.Result{Valid: true}, nil
 		}
 	}
 
-	return false, nil
+	return service.Result{
+		Reason:  service.ReasonAnchorMissing,
+		Message: fmt.Sprintf("anchor '%s' not found at '%s'", parsedURI.Fragment, expandedPath),
+	}, nil
+}
+
+func (File) notFound(path string) service.Result {
+	return service.Result{Reason: service.ReasonNotFound, Message: fmt.Sprintf("file '%s' not found", path)}
 }
 
 // checkMarkdownH checks if the link is in a 'h' tag.
@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"nitro/markdown-link-check/internal/service"
 	"nitro/markdown-link-check/internal/service/parser"
 )
 
@@ -80,17 +81,60 @@ func TestFileAuthority(t *testing.T) {
 	}
 }
 
+func TestFileResolve(t *testing.T) {
+	t.Parallel()
+
+	client := File{Path: "something", Parser: &parser.Markdown{}}
+	require.NoError(t, client.Init())
+
+	tests := []struct {
+		message  string
+		filePath string
+		uri      string
+		expected string
+	}{
+		{
+			message:  "resolve a relative link",
+			filePath: "docs/guide/README.md",
+			uri:      "../setup.md",
+			expected: "docs/setup.md",
+		},
+		{
+			message:  "resolve a relative link with an anchor",
+			filePath: "docs/README.md",
+			uri:      "./setup.md#install",
+			expected: "docs/setup.md#install",
+		},
+		{
+			message:  "resolve an anchor to the file itself",
+			filePath: "docs/README.md",
+			uri:      "#install",
+			expected: "docs/README.md#install",
+		},
+	}
+
+	for i := 0; i < len(tests); i++ {
+		tt := tests[i]
+		t.Run("Should "+tt.message, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.expected, client.Resolve(tt.filePath, tt.uri))
+		})
+	}
+}
+
 func TestFileValid(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
-		message   string
-		ctx       context.Context
-		path      string
-		uri       string
-		isValid   bool
-		shouldErr bool
-		reader    func() *fileReaderMock
+		message    string
+		ctx        context.Context
+		path       string
+		uri        string
+		extensions []string
+		isValid    bool
+		shouldErr  bool
+		reason     service.Reason
+		reader     func() *fileReaderMock
 	}{
 		{
 			message:   "attest that the file doesn't exist",
@@ -99,6 +143,7 @@ func TestFileValid(t *testing.T) {
 			uri:       "link",
 			isValid:   false,
 			shouldErr: false,
+			reason:    service.ReasonNotFound,
 			reader: func() *fileReaderMock {
 				var reader fileReaderMock
 				reader.On("fileExists", "link").Return(fileInfoMock{}, false)
@@ -321,6 +366,7 @@ func TestFileValid(t *testing.T) {
 			uri:       "link.md",
 			isValid:   false,
 			shouldErr: false,
+			reason:    service.ReasonNotFound,
 			reader: func() *fileReaderMock {
 				var reader fileReaderMock
 				reader.On("fileExists", "link.md").Return(fileInfoMock{}, false)
@@ -334,6 +380,7 @@ func TestFileValid(t *testing.T) {
 			uri:       "#anchor",
 			isValid:   false,
 			shouldErr: false,
+			reason:    service.ReasonAnchorMissing,
 			reader: func() *fileReaderMock {
 				var reader fileReaderMock
 				reader.On("fileExists", "file.md").Return(fileInfoMock{}, true)
@@ -348,6 +395,7 @@ func TestFileValid(t *testing.T) {
 			uri:       "#anchor",
 			isValid:   false,
 			shouldErr: false,
+			reason:    service.ReasonAnchorMissing,
 			reader: func() *fileReaderMock {
 				var reader fileReaderMock
 				reader.On("fileExists", "file.md").Return(fileInfoMock{}, true)
@@ -355,6 +403,37 @@ func TestFileValid(t *testing.T) {
 				return &reader
 			},
 		},
+		{
+			message:    "attest that the file with a custom extension exists but the anchor doesn't",
+			ctx:        context.Background(),
+			path:       "file.markdown",
+			uri:        "another.markdown#anchor",
+			extensions: []string{".md", ".markdown"},
+			isValid:    false,
+			shouldErr:  false,
+			reason:     service.ReasonAnchorMissing,
+			reader: func() *fileReaderMock {
+				var reader fileReaderMock
+				reader.On("fileExists", "another.markdown").Return(fileInfoMock{}, true)
+				reader.On("readFile", "another.markdown").Return([]byte("# another"), nil)
+				return &reader
+			},
+		},
+		{
+			message:    "attest that the mdx file has the anchor",
+			ctx:        context.Background(),
+			path:       "file.mdx",
+			uri:        "another.mdx#anchor",
+			extensions: []string{".mdx"},
+			isValid:    true,
+			shouldErr:  false,
+			reader: func() *fileReaderMock {
+				var reader fileReaderMock
+				reader.On("fileExists", "another.mdx").Return(fileInfoMock{}, true)
+				reader.On("readFile", "another.mdx").Return([]byte("import Card from './card'\n\n# Anchor"), nil)
+				return &reader
+			},
+		},
 	}
 
 	for i := 0; i < len(tests); i++ {
@@ -368,15 +447,16 @@ func TestFileValid(t *testing.T) {
 			var parser parser.Markdown
 			parser.Init()
 
-			client := File{Path: "something", Parser: parser, reader: reader}
+			client := File{Path: "something", Parser: parser, Extensions: tt.extensions, reader: reader}
 			require.NoError(t, client.Init())
 
-			isValid, err := client.Valid(tt.ctx, tt.path, tt.uri)
+			result, err := client.Valid(tt.ctx, tt.path, tt.uri)
 			require.Equal(t, tt.shouldErr, (err != nil))
 			if err != nil {
 				return
 			}
-			require.Equal(t, tt.isValid, isValid)
+			require.Equal(t, tt.isValid, result.Valid)
+			require.Equal(t, tt.reason, result.Reason)
 		})
 	}
 }
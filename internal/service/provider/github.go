@@ -10,6 +10,8 @@ import (
 	"strings"
 
 	"github.com/google/go-github/github"
+
+	"nitro/markdown-link-check/internal/service"
 )
 
 type gitHubRepository interface {
@@ -62,6 +64,11 @@ func (g *GitHub) Init() error {
 	return nil
 }
 
+// Name returns the provider identification.
+func (GitHub) Name() string {
+	return "github"
+}
+
 // Authority checks if the github provider is responsible to process the entry.
 func (g GitHub) Authority(uri string) bool {
 	for _, expr := range []regexp.Regexp{g.regexRaw, g.regexBase} {
@@ -73,7 +80,7 @@ func (g GitHub) Authority(uri string) bool {
 }
 
 // Valid check if the link is valid.
-func (g GitHub) Valid(ctx context.Context, _, uri string) (bool, error) {
+func (g GitHub) Valid(ctx context.Context, _, uri string) (service.Result, error) {
 	fns := []func(context.Context, string) (bool, error){
 		g.validOwner,
 		g.validCommit,
@@ -84,13 +91,34 @@ func (g GitHub) Valid(ctx context.Context, _, uri string) (bool, error) {
 	for _, fn := range fns {
 		valid, err := fn(ctx, uri)
 		if err != nil {
-			return false, nil
+			return g.failure(err), nil
 		}
 		if valid {
-			return true, nil
+			return service.Result{Valid: true}, nil
 		}
 	}
-	return false, nil
+	return service.Result{Reason: service.ReasonNotFound, Message: "GitHub resource not found"}, nil
+}
+
+// failure translates the error from the GitHub API into a result.
+func (GitHub) failure(err error) service.Result {
+	result := service.Result{Reason: service.ReasonNetwork, Message: err.Error()}
+
+	var errResponse *github.ErrorResponse
+	if !errors.As(err, &errResponse) || (errResponse.Response == nil) {
+		return result
+	}
+
+	result.StatusCode = errResponse.Response.StatusCode
+	switch result.StatusCode {
+	case http.StatusNotFound:
+		result.Reason = service.ReasonNotFound
+	case http.StatusUnauthorized, http.StatusForbidden:
+		result.Reason = service.ReasonForbidden
+	default:
+		result.Reason = service.ReasonStatusCode
+	}
+	return result
 }
 
 func (g *GitHub) initRegex() error {
@@ -231,12 +231,12 @@ func TestGitHubValid(t *testing.T) {
 			client.repository = tt.repository
 			require.NoError(t, client.Init())
 
-			isValid, err := client.Valid(tt.ctx, "", tt.uri)
+			result, err := client.Valid(tt.ctx, "", tt.uri)
 			require.Equal(t, tt.shouldErr, (err != nil))
 			if err != nil {
 				return
 			}
-			require.Equal(t, tt.isValid, isValid)
+			require.Equal(t, tt.isValid, result.Valid)
 		})
 	}
 }
@@ -5,51 +5,278 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"io"
+	"math/rand"
+	"net"
 	"net/http"
 	"net/url"
 	"regexp"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/launcher"
 	"github.com/go-rod/rod/lib/proto"
+	"golang.org/x/time/rate"
+
+	"nitro/markdown-link-check/internal/service"
+)
+
+const (
+	webDefaultRetryBackoff    = time.Second
+	webDefaultRetryMaxBackoff = 30 * time.Second
+
+	// webGitHubAnchorPrefix is the prefix GitHub adds to the ids and names at the rendered Markdown files.
+	webGitHubAnchorPrefix = "user-content-"
+
+	// webScriptRenderedMaxText is the amount of text below which a page is assumed to be rendered by scripts.
+	webScriptRenderedMaxText = 256
+
+	webDefaultBrowserPoolSize = 4
+	webDefaultBrowserTimeout  = 30 * time.Second
+
+	webDefaultRedirectMaxHops = 10
 )
 
-// Rod is very sensitive and for now, the best approach is to have a mutex at the package level protecting all the
-// operations.
-var webBrowserMutex sync.Mutex // nolint: gochecknoglobals
+// Modes to use the browser to verify the anchors.
+const (
+	WebBrowserModeNever    = "never"
+	WebBrowserModeFallback = "fallback"
+	WebBrowserModeAlways   = "always"
+)
+
+var errWebRedirectNotAllowed = errors.New("redirect not allowed") // nolint: gochecknoglobals
 
 type webClient interface {
 	Do(req *http.Request) (*http.Response, error)
 }
 
-type webClientTransport struct {
-	client webClient
+type webConfigRegex struct {
+	expression regexp.Regexp
+	key        string
+	config     WebConfig
 }
 
-func (w webClientTransport) RoundTrip(req *http.Request) (*http.Response, error) {
-	return w.client.Do(req)
+// webLimiter holds a token bucket per host to control the rate of requests.
+type webLimiter struct {
+	mutex    sync.Mutex
+	limiters map[string]*rate.Limiter
 }
 
-type webConfigRegex struct {
-	expression regexp.Regexp
-	key        string
+func (w *webLimiter) wait(ctx context.Context, key string, cfg WebConfigRateLimit) error {
+	if cfg.RequestsPerSecond <= 0 {
+		return nil
+	}
+
+	w.mutex.Lock()
+	limiter, ok := w.limiters[key]
+	if !ok {
+		burst := cfg.Burst
+		if burst <= 0 {
+			burst = 1
+		}
+		limiter = rate.NewLimiter(rate.Limit(cfg.RequestsPerSecond), burst)
+		w.limiters[key] = limiter
+	}
+	w.mutex.Unlock()
+
+	return limiter.Wait(ctx)
+}
+
+// webPagePool holds the browser pages reused across the anchor checks. The pool has a slot per page and the pages are
+// created on demand, so the amount of pages open at the same time is limited by the pool size.
+type webPagePool struct {
+	pages chan *rod.Page
+}
+
+func newWebPagePool(size int) *webPagePool {
+	pages := make(chan *rod.Page, size)
+	for i := 0; i < size; i++ {
+		pages <- nil
+	}
+	return &webPagePool{pages: pages}
 }
 
-// WebConfig has the information to enhance the request.
+// get a page from the pool, the page is created if the slot is empty. It blocks until a slot is available.
+func (w *webPagePool) get(ctx context.Context, create func() (*rod.Page, error)) (*rod.Page, error) {
+	select {
+	case page := <-w.pages:
+		if page != nil {
+			return page, nil
+		}
+		page, err := create()
+		if err != nil {
+			w.pages <- nil
+			return nil, err
+		}
+		return page, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// put the page back at the pool. A nil page releases the slot, which is the case for the pages in a broken state.
+func (w *webPagePool) put(page *rod.Page) {
+	w.pages <- page
+}
+
+// close all the pages. It waits for the pages in use to be returned to the pool.
+func (w *webPagePool) close() error {
+	var result error
+	for i := 0; i < cap(w.pages); i++ {
+		page := <-w.pages
+		if page == nil {
+			continue
+		}
+		if err := page.Close(); (err != nil) && (result == nil) {
+			result = err
+		}
+	}
+	return result
+}
+
+// WebBrowser controls the headless browser used to verify the anchors at the pages rendered by scripts. 'PoolSize'
+// is the amount of pages, reused across the checks, that can be loaded at the same time, it defaults to 4. 'Timeout'
+// limits the time to load a page and find the anchor, it defaults to 30 seconds.
+//
+// The browser is only started when the first page is needed. 'RemoteURL' is the DevTools endpoint of a running
+// browser, like 'ws://127.0.0.1:9222/devtools/browser/<id>' or just '127.0.0.1:9222', when it's empty a local browser
+// is launched instead.
+type WebBrowser struct {
+	PoolSize  int
+	Timeout   time.Duration
+	RemoteURL string
+}
+
+// webBrowser holds the browser and the pool of pages. The browser is connected at the first use.
+type webBrowser struct {
+	config WebBrowser
+	pages  *webPagePool
+
+	mutex   sync.Mutex
+	browser *rod.Browser
+	err     error
+}
+
+// connect to the browser, launching it if needed. A failure is kept and returned at the next calls, so the launch is
+// not retried for every page.
+func (w *webBrowser) connect() (*rod.Browser, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if (w.browser != nil) || (w.err != nil) {
+		return w.browser, w.err
+	}
+
+	var controlURL string
+	if w.config.RemoteURL != "" {
+		controlURL, w.err = launcher.ResolveURL(w.config.RemoteURL)
+		if w.err != nil {
+			w.err = fmt.Errorf("failed to resolve the remote browser URL: %w", w.err)
+			return nil, w.err
+		}
+	} else {
+		controlURL, w.err = launcher.New().Headless(true).Launch()
+		if w.err != nil {
+			w.err = fmt.Errorf("failed to launch the browser: %w", w.err)
+			return nil, w.err
+		}
+	}
+
+	browser := rod.New().ControlURL(controlURL)
+	if err := browser.Connect(); err != nil {
+		w.err = fmt.Errorf("failed to connect to the browser: %w", err)
+		return nil, w.err
+	}
+	w.browser = browser
+	return w.browser, nil
+}
+
+// page returns a page from the pool.
+func (w *webBrowser) page(ctx context.Context) (*rod.Page, error) {
+	return w.pages.get(ctx, func() (*rod.Page, error) {
+		browser, err := w.connect()
+		if err != nil {
+			return nil, err
+		}
+		return browser.Page(proto.TargetCreateTarget{})
+	})
+}
+
+// close the pages and the browser, if it was launched. A remote browser is kept running.
+func (w *webBrowser) close() error {
+	if err := w.pages.close(); err != nil {
+		return fmt.Errorf("failed to close the browser pages: %w", err)
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if (w.browser == nil) || (w.config.RemoteURL != "") {
+		return nil
+	}
+	if err := w.browser.Close(); err != nil {
+		return fmt.Errorf("failed to close the browser: %w", err)
+	}
+	return nil
+}
+
+// WebConfigRateLimit controls the amount of requests sent to a host. The limit is disabled when 'RequestsPerSecond' is
+// zero.
+type WebConfigRateLimit struct {
+	RequestsPerSecond float64
+	Burst             int
+}
+
+// WebConfigRetry controls the retry of requests that failed because of network errors or because of the status codes
+// 429, 502, 503 and 504. 'Max' is the amount of retries, the retry is disabled when it's zero. The delay between the
+// attempts grows exponentially from 'Backoff' up to 'MaxBackoff', unless the response has a 'Retry-After' header.
+type WebConfigRetry struct {
+	Max        int
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+// WebConfigRedirect controls the redirects. 'StatusCodes' has the redirect status codes followed, it defaults to 301
+// and 308, other redirects are invalid links unless their status code is accepted. 'MaxHops' limits the amount of
+// redirects followed, it defaults to 10. 'CrossDomain' allows redirects to other hosts, it defaults to true.
+type WebConfigRedirect struct {
+	StatusCodes []int
+	MaxHops     int
+	CrossDomain *bool
+}
+
+// WebConfig has the information to enhance the request. 'BrowserMode' defines when the anchors are verified at a
+// browser: 'never', 'fallback' when the anchor is not found and the page is rendered by scripts, which is the default,
+// or 'always' when the anchor is not found. 'StatusCodes' has the status codes of the valid links, it defaults to any
+// 2xx status code. 'RateLimit' and 'Retry' are disabled when not set, an overwrite without them uses the global ones
+// and an overwrite with them takes the 'Burst', 'Backoff' and 'MaxBackoff' not set from the global ones.
 type WebConfig struct {
-	Header http.Header
+	Header      http.Header
+	RateLimit   *WebConfigRateLimit
+	Retry       *WebConfigRetry
+	Redirect    WebConfigRedirect
+	StatusCodes []int
+	BrowserMode string
 }
 
-// Web handle the verification of HTTP endpoints.
+// WebConfigOverwrite holds the configuration of the endpoints that match the 'Endpoint' regular expression.
+type WebConfigOverwrite struct {
+	Endpoint string
+	Config   WebConfig
+}
+
+// Web handle the verification of HTTP endpoints. The overwrites are matched in order and the first one that matches
+// the endpoint is used.
 type Web struct {
 	Config          WebConfig
-	ConfigOverwrite map[string]WebConfig
+	ConfigOverwrite []WebConfigOverwrite
+	Browser         WebBrowser
 
-	browser              *rod.Browser
+	browser              *webBrowser
 	client               webClient
+	limiter              *webLimiter
 	regex                regexp.Regexp
 	regexConfigOverwrite []webConfigRegex
 }
@@ -63,6 +290,7 @@ func (w *Web) Init() error {
 		return fmt.Errorf("fail to initialize the regex config: %w", err)
 	}
 	w.initHTTP()
+	w.initLimiter()
 	if err := w.initBrowser(); err != nil {
 		return fmt.Errorf("failed to initialize the browser: %w", err)
 	}
@@ -71,10 +299,12 @@ func (w *Web) Init() error {
 
 // Close the provider.
 func (w *Web) Close() error {
-	if err := w.browser.Close(); err != nil {
-		return fmt.Errorf("failed to close the browser: %w", err)
-	}
-	return nil
+	return w.browser.close()
+}
+
+// Name returns the provider identification.
+func (Web) Name() string {
+	return "web"
 }
 
 // Authority checks if the web provider is responsible to process the entry.
@@ -83,69 +313,274 @@ func (w Web) Authority(uri string) bool {
 }
 
 // Valid check if the link is valid.
-func (w Web) Valid(ctx context.Context, _, uri string) (bool, error) {
+func (w Web) Valid(ctx context.Context, _, uri string) (service.Result, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
 	if err != nil {
-		return false, fmt.Errorf("fail to create the HTTP request: %w", err)
+		return service.Result{}, fmt.Errorf("fail to create the HTTP request: %w", err)
 	}
 	w.configRequest(req)
 
-	resp, err := w.client.Do(req)
+	resp, err := w.do(ctx, req)
 	if err != nil {
-		return false, nil
+		if ctx.Err() != nil {
+			return service.Result{}, fmt.Errorf("fail to execute the HTTP request: %w", err)
+		}
+		return w.failure(err), nil
 	}
 	defer resp.Body.Close()
 
 	endpoint, err := url.Parse(uri)
 	if err != nil {
-		return false, fmt.Errorf("fail to parse uri: %w", err)
+		return service.Result{}, fmt.Errorf("fail to parse uri: %w", err)
 	}
 
-	isValid := ((resp.StatusCode >= 200) && (resp.StatusCode < 300))
-	if !isValid {
-		return false, nil
+	redirects := w.redirects(resp)
+	if !w.acceptedStatusCode(uri, resp.StatusCode) {
+		result := w.failureStatusCode(resp.StatusCode)
+		result.Redirects = redirects
+		return result, nil
 	}
 
-	validAnchor, err := w.validAnchor(resp.Body, endpoint.Fragment)
-	if err != nil {
-		return false, fmt.Errorf("fail to verify the anchor: %w", err)
+	result := service.Result{Valid: true, StatusCode: resp.StatusCode, Redirects: redirects}
+	if w.permanentRedirect(redirects) {
+		result.Severity = service.SeverityWarning
+		result.Reason = service.ReasonPermanentRedirect
+		result.Message = fmt.Sprintf("permanently redirected to '%s'", redirects[len(redirects)-1].Link)
 	}
-	if validAnchor {
-		return true, nil
+	if endpoint.Fragment == "" {
+		return result, nil
 	}
 
-	validAnchor, err = w.validAnchorBrowser(ctx, uri, endpoint.Fragment)
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
 	if err != nil {
-		return false, fmt.Errorf("fail to verify the anchor with a browser: %w", err)
+		return service.Result{}, fmt.Errorf("fail to parse the response: %w", err)
+	}
+	validAnchor := w.validAnchor(doc, endpoint.Fragment)
+
+	if !validAnchor && w.useBrowser(uri, doc) {
+		validAnchor, err = w.validAnchorBrowser(ctx, uri, endpoint.Fragment)
+		if err != nil {
+			return service.Result{}, fmt.Errorf("fail to verify the anchor with a browser: %w", err)
+		}
+	}
+	if !validAnchor {
+		result.Valid = false
+		result.Severity = ""
+		result.Reason = service.ReasonAnchorMissing
+		result.Message = fmt.Sprintf("anchor '%s' not found", endpoint.Fragment)
+	}
+	return result, nil
+}
+
+// redirects returns the redirect chain followed to get the response. The locations without a fragment inherit the
+// fragment of the previous request, like the browsers do.
+func (Web) redirects(resp *http.Response) []service.Redirect {
+	var requests []*http.Request
+	for req := resp.Request; (req != nil) && (req.Response != nil); req = req.Response.Request {
+		requests = append(requests, req)
 	}
-	return validAnchor, nil
+	if len(requests) == 0 {
+		return nil
+	}
+
+	var (
+		result   = make([]service.Redirect, 0, len(requests))
+		fragment = requests[len(requests)-1].Response.Request.URL.Fragment
+	)
+	for i := len(requests) - 1; i >= 0; i-- {
+		location := *requests[i].URL
+		if location.Fragment == "" {
+			location.Fragment = fragment
+		}
+		fragment = location.Fragment
+		result = append(result, service.Redirect{Link: location.String(), StatusCode: requests[i].Response.StatusCode})
+	}
+	return result
 }
 
-func (w Web) validAnchor(body io.Reader, anchor string) (bool, error) {
-	if anchor == "" {
-		return true, nil
+// permanentRedirect checks if the link is permanently redirected, which is the case when every redirect followed is
+// permanent. The link can then be replaced by the final location.
+func (Web) permanentRedirect(redirects []service.Redirect) bool {
+	for _, redirect := range redirects {
+		if (redirect.StatusCode != http.StatusMovedPermanently) && (redirect.StatusCode != http.StatusPermanentRedirect) {
+			return false
+		}
+	}
+	return len(redirects) > 0
+}
+
+// failure translates the error from the HTTP client into a result.
+func (Web) failure(err error) service.Result {
+	result := service.Result{Reason: service.ReasonNetwork, Message: err.Error()}
+
+	var netErr net.Error
+	switch {
+	case errors.Is(err, errWebRedirectNotAllowed):
+		result.Reason = service.ReasonRedirect
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		result.Reason = service.ReasonTimeout
+	}
+	return result
+}
+
+func (Web) failureStatusCode(statusCode int) service.Result {
+	result := service.Result{
+		Reason:     service.ReasonStatusCode,
+		StatusCode: statusCode,
+		Message:    fmt.Sprintf("status code %d (%s)", statusCode, http.StatusText(statusCode)),
+	}
+
+	switch statusCode {
+	case http.StatusNotFound, http.StatusGone:
+		result.Reason = service.ReasonNotFound
+	case http.StatusUnauthorized, http.StatusForbidden:
+		result.Reason = service.ReasonForbidden
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		result.Reason = service.ReasonTimeout
+	}
+	return result
+}
+
+// do execute the request and retry it based on the retry configuration of the endpoint.
+func (w Web) do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	cfg := w.retryConfig(req.URL.String())
+	for attempt := 0; ; attempt++ {
+		if err := w.wait(ctx, req.URL); err != nil {
+			return nil, fmt.Errorf("fail to wait for the rate limiter: %w", err)
+		}
+
+		resp, err := w.client.Do(req.Clone(ctx))
+		if (attempt >= cfg.Max) || !w.retryable(ctx, resp, err) {
+			return resp, err
+		}
+
+		delay := w.backoff(cfg, attempt, resp)
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
 	}
-	anchor = fmt.Sprintf("#%s", anchor)
+}
 
-	doc, err := goquery.NewDocumentFromReader(body)
+func (Web) retryable(ctx context.Context, resp *http.Response, err error) bool {
 	if err != nil {
-		return false, fmt.Errorf("failt o parse the response: %w", err)
+		return (ctx.Err() == nil) && !errors.Is(err, errWebRedirectNotAllowed)
 	}
 
-	var found bool
-	doc.Find("a").Each(func(_ int, selection *goquery.Selection) {
-		if found {
-			return
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// backoff calculates the delay before the next attempt. The 'Retry-After' header has precedence over the exponential
+// backoff, in both cases the delay is limited by the max backoff.
+func (w Web) backoff(cfg WebConfigRetry, attempt int, resp *http.Response) time.Duration {
+	if delay, ok := w.retryAfter(resp); ok {
+		if delay > cfg.MaxBackoff {
+			return cfg.MaxBackoff
 		}
+		return delay
+	}
+
+	delay := cfg.Backoff << uint(attempt)
+	if (delay <= 0) || (delay > cfg.MaxBackoff) {
+		delay = cfg.MaxBackoff
+	}
 
-		href, ok := selection.Attr("href")
-		if !ok {
-			return
+	// Half of the delay is randomized to avoid multiple requests retrying at the same time.
+	half := int64(delay / 2)
+	return time.Duration(half + rand.Int63n(half+1)) // nolint: gosec
+}
+
+func (Web) retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
 		}
-		found = (href == anchor)
-	})
+		return time.Duration(seconds) * time.Second, true
+	}
 
-	return found, nil
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	if delay := time.Until(date); delay > 0 {
+		return delay, true
+	}
+	return 0, true
+}
+
+// retryConfig returns the retry configuration of the endpoint, the overwrite one when set, otherwise the global one.
+// The backoffs not set at the overwrite configuration are taken from the global one.
+func (w Web) retryConfig(endpoint string) WebConfigRetry {
+	var result WebConfigRetry
+	if w.Config.Retry != nil {
+		result = *w.Config.Retry
+	}
+	if _, overwrite, ok := w.configOverwrite(endpoint); ok && (overwrite.Retry != nil) {
+		result.Max = overwrite.Retry.Max
+		if overwrite.Retry.Backoff > 0 {
+			result.Backoff = overwrite.Retry.Backoff
+		}
+		if overwrite.Retry.MaxBackoff > 0 {
+			result.MaxBackoff = overwrite.Retry.MaxBackoff
+		}
+	}
+
+	if result.Backoff <= 0 {
+		result.Backoff = webDefaultRetryBackoff
+	}
+	if result.MaxBackoff <= 0 {
+		result.MaxBackoff = webDefaultRetryMaxBackoff
+	}
+	return result
+}
+
+// validAnchor checks if the document has the anchor target, an element with the anchor as id or an 'a' tag with the
+// anchor as name. GitHub prefixes the ids and the names at the rendered Markdown files with 'user-content-' and
+// resolves the anchors with scripts, so the prefixed targets are accepted as well.
+func (Web) validAnchor(doc *goquery.Document, anchor string) bool {
+	var found bool
+	find := func(selector, attr string) {
+		doc.Find(selector).EachWithBreak(func(_ int, selection *goquery.Selection) bool {
+			value, _ := selection.Attr(attr)
+			found = (value == anchor) || (value == webGitHubAnchorPrefix+anchor)
+			return !found
+		})
+	}
+
+	find("[id]", "id")
+	if !found {
+		find("a[name]", "name")
+	}
+	return found
+}
+
+// scriptRendered checks if the document content is rendered by scripts, which is assumed when the document has
+// almost no text besides the scripts.
+func (Web) scriptRendered(doc *goquery.Document) bool {
+	body := doc.Find("body").Clone()
+	body.Find("script, style, noscript, template").Remove()
+	return len(strings.TrimSpace(body.Text())) < webScriptRenderedMaxText
 }
 
 func (w *Web) initRegex() error {
@@ -159,81 +594,210 @@ func (w *Web) initRegex() error {
 }
 
 func (w *Web) initRegexConfig() error {
-	w.regexConfigOverwrite = make([]webConfigRegex, 0, len(w.regexConfigOverwrite))
-	for key := range w.ConfigOverwrite {
-		regex, err := regexp.Compile(key)
+	var (
+		endpoints = make(map[string]struct{}, len(w.ConfigOverwrite))
+		configs   = make([]webConfigRegex, 0, len(w.ConfigOverwrite))
+	)
+	for _, overwrite := range w.ConfigOverwrite {
+		if _, ok := endpoints[overwrite.Endpoint]; ok {
+			return fmt.Errorf("duplicated overwrite endpoint '%s'", overwrite.Endpoint)
+		}
+		endpoints[overwrite.Endpoint] = struct{}{}
+
+		regex, err := regexp.Compile(overwrite.Endpoint)
 		if err != nil {
-			return fmt.Errorf("fail to compile the expression '%s': %w", key, err)
+			return fmt.Errorf("fail to compile the expression '%s': %w", overwrite.Endpoint, err)
 		}
-		w.regexConfigOverwrite = append(w.regexConfigOverwrite, webConfigRegex{key: key, expression: *regex})
+		configs = append(configs, webConfigRegex{key: overwrite.Endpoint, expression: *regex, config: overwrite.Config})
 	}
+	w.regexConfigOverwrite = configs
 	return nil
 }
 
 func (w *Web) initHTTP() {
 	w.client = &http.Client{
-		Transport: webClientTransport{client: http.DefaultClient},
+		Transport: http.DefaultTransport,
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
-			switch req.Response.StatusCode {
-			case http.StatusPermanentRedirect, http.StatusMovedPermanently:
-				return nil
-			default:
-				return errors.New("redirect not allowed")
-			}
+			return w.checkRedirect(req, via)
 		},
 	}
 }
 
-func (w *Web) initBrowser() error {
-	webBrowserMutex.Lock()
-	defer webBrowserMutex.Unlock()
+// checkRedirect applies the redirect configuration of the original endpoint. The redirects not followed, but with an
+// accepted status code, are returned as the response.
+func (w Web) checkRedirect(req *http.Request, via []*http.Request) error {
+	var (
+		endpoint   = via[0].URL.String()
+		cfg        = w.redirectConfig(endpoint)
+		statusCode = req.Response.StatusCode
+	)
+
+	if !webContains(cfg.StatusCodes, statusCode) {
+		if w.acceptedStatusCode(endpoint, statusCode) {
+			return http.ErrUseLastResponse
+		}
+		return fmt.Errorf("%w: status code %d", errWebRedirectNotAllowed, statusCode)
+	}
+	if len(via) > cfg.MaxHops {
+		return fmt.Errorf("%w: more than %d redirects", errWebRedirectNotAllowed, cfg.MaxHops)
+	}
+	if !*cfg.CrossDomain && !strings.EqualFold(req.URL.Hostname(), via[0].URL.Hostname()) {
+		return fmt.Errorf("%w: cross-domain redirect to '%s'", errWebRedirectNotAllowed, req.URL.Hostname())
+	}
+	return nil
+}
 
-	launcherURL, err := launcher.New().Headless(true).Launch()
-	if err != nil {
-		return fmt.Errorf("failed to launch the browser: %w", err)
+// redirectConfig returns the redirect configuration of the endpoint. Each field not set at the overwrite configuration
+// is taken from the global one.
+func (w Web) redirectConfig(endpoint string) WebConfigRedirect {
+	result := w.Config.Redirect
+	if _, cfg, ok := w.configOverwrite(endpoint); ok {
+		if len(cfg.Redirect.StatusCodes) > 0 {
+			result.StatusCodes = cfg.Redirect.StatusCodes
+		}
+		if cfg.Redirect.MaxHops > 0 {
+			result.MaxHops = cfg.Redirect.MaxHops
+		}
+		if cfg.Redirect.CrossDomain != nil {
+			result.CrossDomain = cfg.Redirect.CrossDomain
+		}
+	}
+
+	if len(result.StatusCodes) == 0 {
+		result.StatusCodes = []int{http.StatusMovedPermanently, http.StatusPermanentRedirect}
+	}
+	if result.MaxHops <= 0 {
+		result.MaxHops = webDefaultRedirectMaxHops
+	}
+	if result.CrossDomain == nil {
+		crossDomain := true
+		result.CrossDomain = &crossDomain
+	}
+	return result
+}
+
+// acceptedStatusCode checks if the status code is accepted as valid for the endpoint.
+func (w Web) acceptedStatusCode(endpoint string, statusCode int) bool {
+	_, cfg, ok := w.configOverwrite(endpoint)
+	if !ok || (len(cfg.StatusCodes) == 0) {
+		cfg = w.Config
+	}
+	if len(cfg.StatusCodes) == 0 {
+		return (statusCode >= 200) && (statusCode < 300)
 	}
+	return webContains(cfg.StatusCodes, statusCode)
+}
 
-	w.browser = rod.New().ControlURL(launcherURL)
-	if err = w.browser.Connect(); err != nil {
-		return fmt.Errorf("failed to connect to the browser: %w", err)
+func webContains(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *Web) initLimiter() {
+	w.limiter = &webLimiter{limiters: make(map[string]*rate.Limiter)}
+}
+
+func (w *Web) initBrowser() error {
+	if w.Browser.PoolSize <= 0 {
+		w.Browser.PoolSize = webDefaultBrowserPoolSize
+	}
+	if w.Browser.Timeout <= 0 {
+		w.Browser.Timeout = webDefaultBrowserTimeout
+	}
+	w.browser = &webBrowser{config: w.Browser, pages: newWebPagePool(w.Browser.PoolSize)}
+
+	configs := []WebConfig{w.Config}
+	for _, overwrite := range w.ConfigOverwrite {
+		configs = append(configs, overwrite.Config)
+	}
+	for _, cfg := range configs {
+		switch cfg.BrowserMode {
+		case "", WebBrowserModeNever, WebBrowserModeFallback, WebBrowserModeAlways:
+		default:
+			return fmt.Errorf("unknown browser mode '%s'", cfg.BrowserMode)
+		}
 	}
 	return nil
 }
 
-func (w Web) validAnchorBrowser(ctx context.Context, endpoint string, anchor string) (_ bool, err error) {
-	webBrowserMutex.Lock()
-	defer webBrowserMutex.Unlock()
+// useBrowser checks if the anchor, not found at the document, should be verified at the browser. With the fallback
+// mode the browser is only needed when the page is rendered by scripts, otherwise the anchor is really missing.
+func (w Web) useBrowser(endpoint string, doc *goquery.Document) bool {
+	_, cfg, ok := w.configOverwrite(endpoint)
+	if !ok || (cfg.BrowserMode == "") {
+		cfg = w.Config
+	}
+
+	switch cfg.BrowserMode {
+	case WebBrowserModeNever:
+		return false
+	case WebBrowserModeAlways:
+		return true
+	default:
+		return w.scriptRendered(doc)
+	}
+}
 
-	pctx, pctxCancel := context.WithCancel(ctx)
-	defer pctxCancel()
+func (w Web) validAnchorBrowser(ctx context.Context, endpoint string, anchor string) (bool, error) {
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil {
+		return false, fmt.Errorf("fail to parse the endpoint: %w", err)
+	}
 
-	page, err := w.browser.Page(proto.TargetCreateTarget{})
+	page, err := w.browser.page(ctx)
 	if err != nil {
 		return false, fmt.Errorf("failed to create the browser page: %w", err)
 	}
-	defer func() {
-		if perr := page.Close(); perr != nil {
-			err = fmt.Errorf("failed to close the browser tab: %w", perr)
+
+	result, err := w.loadPage(ctx, page, endpointURL)
+	if err != nil {
+		// The page can be in any state after a failure, so it's discarded instead of being reused. The error to close
+		// it is added to the original one.
+		if errClose := page.Close(); errClose != nil {
+			err = fmt.Errorf("%w (failed to close the browser page: %s)", err, errClose.Error())
 		}
-	}()
+		w.browser.pages.put(nil)
+		return false, err
+	}
+	w.browser.pages.put(page)
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewBufferString(result))
+	if err != nil {
+		return false, fmt.Errorf("fail to parse the page: %w", err)
+	}
+	return w.validAnchor(doc, anchor), nil
+}
+
+// loadPage navigates to the endpoint and returns the HTML rendered at the page.
+func (w Web) loadPage(ctx context.Context, page *rod.Page, endpoint *url.URL) (string, error) {
+	if err := w.wait(ctx, endpoint); err != nil {
+		return "", fmt.Errorf("fail to wait for the rate limiter: %w", err)
+	}
+
+	page = page.Context(ctx).Timeout(w.Browser.Timeout)
+	defer page.CancelTimeout()
 
-	if _, err = page.Context(pctx).SetExtraHeaders(w.genHeaders(endpoint)); err != nil {
-		return false, fmt.Errorf("failed to set the headers at the browser page: %w", err)
+	if _, err := page.SetExtraHeaders(w.genHeaders(endpoint.String())); err != nil {
+		return "", fmt.Errorf("failed to set the headers at the browser page: %w", err)
 	}
 
-	if err := page.Navigate(endpoint); err != nil {
-		return false, fmt.Errorf("failed to navigate to the page: %w", err)
+	if err := page.Navigate(endpoint.String()); err != nil {
+		return "", fmt.Errorf("failed to navigate to the page: %w", err)
 	}
 
 	if err := page.WaitLoad(); err != nil {
-		return false, fmt.Errorf("failed to wait for the page to load: %w", err)
+		return "", fmt.Errorf("failed to wait for the page to load: %w", err)
 	}
 
 	result, err := page.Eval("", "document.documentElement.innerHTML", nil)
 	if err != nil {
-		return false, fmt.Errorf("failed to execute the javascript at the page: %w", err)
+		return "", fmt.Errorf("failed to execute the javascript at the page: %w", err)
 	}
-	return w.validAnchor(bytes.NewBufferString(result.Value.String()), anchor)
+	return result.Value.String(), nil
 }
 
 func (w Web) configRequest(r *http.Request) {
@@ -247,12 +811,8 @@ func (w Web) configRequest(r *http.Request) {
 
 	setHeader(w.Config.Header)
 
-	endpoint := r.URL.String()
-	for _, cfg := range w.regexConfigOverwrite {
-		if cfg.expression.Match([]byte(endpoint)) {
-			setHeader(w.ConfigOverwrite[cfg.key].Header)
-			return
-		}
+	if _, cfg, ok := w.configOverwrite(r.URL.String()); ok {
+		setHeader(cfg.Header)
 	}
 }
 
@@ -268,11 +828,8 @@ func (w Web) genHeaders(endpoint string) []string {
 	}
 	setHeader(w.Config.Header)
 
-	for _, cfg := range w.regexConfigOverwrite {
-		if cfg.expression.MatchString(endpoint) {
-			setHeader(w.ConfigOverwrite[cfg.key].Header)
-			break
-		}
+	if _, cfg, ok := w.configOverwrite(endpoint); ok {
+		setHeader(cfg.Header)
 	}
 
 	results := make([]string, 0, len(header)*2)
@@ -281,3 +838,31 @@ func (w Web) genHeaders(endpoint string) []string {
 	}
 	return results
 }
+
+// wait blocks until the rate limiter of the endpoint host allows a new request. Overwrites with a rate limit have their
+// own bucket per host, or no limit at all when the rate limit is disabled. The burst not set at the overwrite
+// configuration is taken from the global one.
+func (w Web) wait(ctx context.Context, endpoint *url.URL) error {
+	key, cfg := "", w.Config.RateLimit
+	if overwriteKey, overwrite, ok := w.configOverwrite(endpoint.String()); ok && (overwrite.RateLimit != nil) {
+		rateLimit := *overwrite.RateLimit
+		if (rateLimit.Burst <= 0) && (w.Config.RateLimit != nil) {
+			rateLimit.Burst = w.Config.RateLimit.Burst
+		}
+		key, cfg = overwriteKey, &rateLimit
+	}
+	if cfg == nil {
+		return nil
+	}
+	return w.limiter.wait(ctx, key+"|"+endpoint.Host, *cfg)
+}
+
+// configOverwrite returns the first overwrite configuration that matches the endpoint.
+func (w Web) configOverwrite(endpoint string) (string, WebConfig, bool) {
+	for _, cfg := range w.regexConfigOverwrite {
+		if cfg.expression.MatchString(endpoint) {
+			return cfg.key, cfg.config, true
+		}
+	}
+	return "", WebConfig{}, false
+}
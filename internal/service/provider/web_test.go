@@ -2,19 +2,89 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/PuerkitoBio/goquery"
+	"github.com/go-rod/rod"
 	"github.com/stretchr/testify/require"
+
+	"nitro/markdown-link-check/internal/service"
 )
 
 func TestWebInit(t *testing.T) {
 	t.Parallel()
 	var client Web
 	require.NoError(t, client.Init())
+
+	client = Web{Config: WebConfig{BrowserMode: "sometimes"}}
+	require.Error(t, client.Init())
+
+	client = Web{
+		ConfigOverwrite: []WebConfigOverwrite{
+			{Endpoint: "https://example.com"},
+			{Endpoint: "https://example.com"},
+		},
+	}
+	require.Error(t, client.Init())
+}
+
+func TestWebConfigOverwrite(t *testing.T) {
+	t.Parallel()
+
+	client := Web{
+		ConfigOverwrite: []WebConfigOverwrite{
+			{Endpoint: "^https://example.com/docs", Config: WebConfig{BrowserMode: WebBrowserModeAlways}},
+			{Endpoint: "^https://example.com", Config: WebConfig{BrowserMode: WebBrowserModeNever}},
+		},
+	}
+	require.NoError(t, client.initRegexConfig())
+
+	tests := []struct {
+		message  string
+		endpoint string
+		key      string
+		mode     string
+		found    bool
+	}{
+		{
+			message:  "use the first overwrite that matches the endpoint",
+			endpoint: "https://example.com/docs/page",
+			key:      "^https://example.com/docs",
+			mode:     WebBrowserModeAlways,
+			found:    true,
+		},
+		{
+			message:  "use the next overwrite when the first doesn't match the endpoint",
+			endpoint: "https://example.com/blog",
+			key:      "^https://example.com",
+			mode:     WebBrowserModeNever,
+			found:    true,
+		},
+		{
+			message:  "not find an overwrite",
+			endpoint: "https://other.com",
+			found:    false,
+		},
+	}
+
+	for i := 0; i < len(tests); i++ {
+		tt := tests[i]
+		t.Run("Should "+tt.message, func(t *testing.T) {
+			t.Parallel()
+
+			key, cfg, found := client.configOverwrite(tt.endpoint)
+			require.Equal(t, tt.found, found)
+			require.Equal(t, tt.key, key)
+			require.Equal(t, tt.mode, cfg.BrowserMode)
+		})
+	}
 }
 
 func TestWebAuthority(t *testing.T) {
@@ -90,7 +160,7 @@ func TestWebValid(t *testing.T) {
 		}
 
 		if r.URL.Path == "/valid-fragment-title" {
-			_, err := w.Write([]byte(`<a href="#title"/>`))
+			_, err := w.Write([]byte(`<h1 id="title">Title</h1>`))
 			require.NoError(t, err)
 			return
 		}
@@ -98,7 +168,7 @@ func TestWebValid(t *testing.T) {
 		if r.URL.Path == "/valid-fragment-title-from-browser" {
 			var response string
 			if r.Header.Get("user-agent") != "Go-http-client/1.1" {
-				response = `<a href="#title"/>`
+				response = `<h1 id="title">Title</h1>`
 			}
 			_, err := w.Write([]byte(response))
 			require.NoError(t, err)
@@ -135,7 +205,7 @@ func TestWebValid(t *testing.T) {
 
 		if r.URL.Path == "/invalid-fragment-broken" {
 			require.Equal(t, "true", r.Header.Get("control-browser"))
-			_, err := w.Write([]byte(`<a href="#title"/>`))
+			_, err := w.Write([]byte(`<h1 id="title">Title</h1><p>` + strings.Repeat("Some text. ", 30) + `</p>`))
 			require.NoError(t, err)
 			return
 		}
@@ -147,10 +217,11 @@ func TestWebValid(t *testing.T) {
 	require.NoError(t, err)
 
 	tests := []struct {
-		message   string
-		endpoint  url.URL
-		isValid   bool
-		shouldErr bool
+		message     string
+		endpoint    url.URL
+		browserMode string
+		isValid     bool
+		shouldErr   bool
 	}{
 		{
 			message:   "attest the URI as valid",
@@ -212,6 +283,13 @@ func TestWebValid(t *testing.T) {
 			shouldErr: false,
 			isValid:   false,
 		},
+		{
+			message:     "attest the URI as invalid because of a not found anchor without the browser",
+			endpoint:    url.URL{Path: "/valid-fragment-title-from-browser", Fragment: "broken"},
+			browserMode: WebBrowserModeNever,
+			shouldErr:   false,
+			isValid:     false,
+		},
 	}
 
 	genEndpoint := func(endpoint url.URL) string {
@@ -228,25 +306,517 @@ func TestWebValid(t *testing.T) {
 		tt := tests[i]
 		t.Run("Should "+tt.message, func(t *testing.T) {
 			client := Web{
-				Config: WebConfig{Header: make(http.Header)},
-				ConfigOverwrite: map[string]WebConfig{
-					"http://localhost": {Header: make(http.Header)},
-					"http://127.0.0.1": {Header: make(http.Header)},
+				Config: WebConfig{Header: make(http.Header), BrowserMode: tt.browserMode},
+				ConfigOverwrite: []WebConfigOverwrite{
+					{Endpoint: "http://localhost", Config: WebConfig{Header: make(http.Header)}},
+					{Endpoint: "http://127.0.0.1", Config: WebConfig{Header: make(http.Header)}},
 				},
 			}
 			client.Config.Header.Set("control", "true")
 			client.Config.Header.Set("user-agent", "firefox")
-			client.ConfigOverwrite["http://localhost"].Header.Set("user-agent", "chrome")
-			client.ConfigOverwrite["http://127.0.0.1"].Header.Set("control-browser", "true")
+			client.ConfigOverwrite[0].Config.Header.Set("user-agent", "chrome")
+			client.ConfigOverwrite[1].Config.Header.Set("control-browser", "true")
 			require.NoError(t, client.Init())
 			defer client.Close()
 
-			isValid, err := client.Valid(context.Background(), "", genEndpoint(tt.endpoint))
+			result, err := client.Valid(context.Background(), "", genEndpoint(tt.endpoint))
 			require.Equal(t, tt.shouldErr, (err != nil))
 			if err != nil {
 				return
 			}
-			require.Equal(t, tt.isValid, isValid)
+			require.Equal(t, tt.isValid, result.Valid)
+		})
+	}
+}
+
+func TestWebLimiterWait(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		message     string
+		cfg         WebConfigRateLimit
+		keys        []string
+		minDuration time.Duration
+		maxDuration time.Duration
+	}{
+		{
+			message:     "not wait when the rate limit is disabled",
+			cfg:         WebConfigRateLimit{},
+			keys:        []string{"host", "host", "host"},
+			maxDuration: 50 * time.Millisecond,
+		},
+		{
+			message:     "wait between the requests to the same host",
+			cfg:         WebConfigRateLimit{RequestsPerSecond: 20, Burst: 1},
+			keys:        []string{"host", "host", "host"},
+			minDuration: 90 * time.Millisecond,
+			maxDuration: time.Second,
+		},
+		{
+			message:     "not wait between the requests to different hosts",
+			cfg:         WebConfigRateLimit{RequestsPerSecond: 1, Burst: 1},
+			keys:        []string{"host1", "host2", "host3"},
+			maxDuration: 50 * time.Millisecond,
+		},
+	}
+
+	for i := 0; i < len(tests); i++ {
+		tt := tests[i]
+		t.Run("Should "+tt.message, func(t *testing.T) {
+			t.Parallel()
+
+			var client Web
+			client.initLimiter()
+
+			start := time.Now()
+			for _, key := range tt.keys {
+				require.NoError(t, client.limiter.wait(context.Background(), key, tt.cfg))
+			}
+			elapsed := time.Since(start)
+			require.GreaterOrEqual(t, int64(elapsed), int64(tt.minDuration))
+			require.LessOrEqual(t, int64(elapsed), int64(tt.maxDuration))
+		})
+	}
+}
+
+func TestWebWait(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		message     string
+		endpoint    string
+		minDuration time.Duration
+		maxDuration time.Duration
+	}{
+		{
+			message:     "wait with the global rate limit",
+			endpoint:    "https://limited.com",
+			minDuration: 90 * time.Millisecond,
+			maxDuration: time.Second,
+		},
+		{
+			message:     "not wait because the overwrite disables the rate limit",
+			endpoint:    "https://unlimited.com",
+			maxDuration: 50 * time.Millisecond,
+		},
+		{
+			message:     "wait with the overwrite rate limit and the burst from the global rate limit",
+			endpoint:    "https://burst.com",
+			minDuration: 900 * time.Millisecond,
+			maxDuration: 1500 * time.Millisecond,
+		},
+	}
+
+	for i := 0; i < len(tests); i++ {
+		tt := tests[i]
+		t.Run("Should "+tt.message, func(t *testing.T) {
+			t.Parallel()
+
+			client := Web{
+				Config: WebConfig{RateLimit: &WebConfigRateLimit{RequestsPerSecond: 10, Burst: 2}},
+				ConfigOverwrite: []WebConfigOverwrite{
+					{Endpoint: "^https://unlimited.com", Config: WebConfig{RateLimit: &WebConfigRateLimit{}}},
+					{
+						Endpoint: "^https://burst.com",
+						Config:   WebConfig{RateLimit: &WebConfigRateLimit{RequestsPerSecond: 1}},
+					},
+				},
+			}
+			require.NoError(t, client.initRegexConfig())
+			client.initLimiter()
+
+			endpoint, err := url.Parse(tt.endpoint)
+			require.NoError(t, err)
+
+			start := time.Now()
+			for i := 0; i < 3; i++ {
+				require.NoError(t, client.wait(context.Background(), endpoint))
+			}
+			elapsed := time.Since(start)
+			require.GreaterOrEqual(t, int64(elapsed), int64(tt.minDuration))
+			require.LessOrEqual(t, int64(elapsed), int64(tt.maxDuration))
+		})
+	}
+}
+
+func TestWebValidRetry(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mutex    sync.Mutex
+		attempts = make(map[string]int)
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		attempts[r.URL.Path]++
+		attempt := attempts[r.URL.Path]
+		mutex.Unlock()
+
+		switch r.URL.Path {
+		case "/503-then-200":
+			if attempt < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		case "/429-retry-after":
+			if attempt < 2 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+			}
+		case "/503", "/503-no-retry":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case "/404":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			require.FailNow(t, "not expected to reach this point")
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		message  string
+		path     string
+		isValid  bool
+		reason   service.Reason
+		attempts int
+	}{
+		{
+			message:  "attest the URI as valid after retrying a service unavailable status",
+			path:     "/503-then-200",
+			isValid:  true,
+			attempts: 3,
+		},
+		{
+			message:  "attest the URI as valid after retrying a too many requests status",
+			path:     "/429-retry-after",
+			isValid:  true,
+			attempts: 2,
+		},
+		{
+			message:  "attest the URI as invalid after exhausting the retries",
+			path:     "/503",
+			isValid:  false,
+			reason:   service.ReasonStatusCode,
+			attempts: 4,
+		},
+		{
+			message:  "attest the URI as invalid without retrying because the overwrite disables the retry",
+			path:     "/503-no-retry",
+			isValid:  false,
+			reason:   service.ReasonStatusCode,
+			attempts: 1,
+		},
+		{
+			message:  "attest the URI as invalid without retrying a not found status",
+			path:     "/404",
+			isValid:  false,
+			reason:   service.ReasonNotFound,
+			attempts: 1,
+		},
+	}
+
+	client := Web{
+		Config: WebConfig{Retry: &WebConfigRetry{Max: 3, Backoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}},
+		ConfigOverwrite: []WebConfigOverwrite{
+			{Endpoint: "no-retry$", Config: WebConfig{Retry: &WebConfigRetry{}}},
+		},
+	}
+	require.NoError(t, client.initRegexConfig())
+	client.initHTTP()
+	client.initLimiter()
+
+	for i := 0; i < len(tests); i++ {
+		tt := tests[i]
+		t.Run("Should "+tt.message, func(t *testing.T) {
+			result, err := client.Valid(context.Background(), "", server.URL+tt.path)
+			require.NoError(t, err)
+			require.Equal(t, tt.isValid, result.Valid)
+			require.Equal(t, tt.reason, result.Reason)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			require.Equal(t, tt.attempts, attempts[tt.path])
+		})
+	}
+}
+
+func TestWebRetryConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		message  string
+		endpoint string
+		expected WebConfigRetry
+	}{
+		{
+			message:  "use the global configuration",
+			endpoint: "https://website.com",
+			expected: WebConfigRetry{Max: 3, Backoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond},
+		},
+		{
+			message:  "take the backoffs not set at the overwrite from the global configuration",
+			endpoint: "https://retry.com",
+			expected: WebConfigRetry{Max: 5, Backoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond},
+		},
+		{
+			message:  "use the backoffs set at the overwrite",
+			endpoint: "https://backoff.com",
+			expected: WebConfigRetry{Max: 3, Backoff: 2 * time.Millisecond, MaxBackoff: 5 * time.Millisecond},
+		},
+		{
+			message:  "disable the retry at the overwrite",
+			endpoint: "https://no-retry.com",
+			expected: WebConfigRetry{Backoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond},
+		},
+	}
+
+	client := Web{
+		Config: WebConfig{Retry: &WebConfigRetry{Max: 3, Backoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}},
+		ConfigOverwrite: []WebConfigOverwrite{
+			{Endpoint: "^https://retry.com", Config: WebConfig{Retry: &WebConfigRetry{Max: 5}}},
+			{
+				Endpoint: "^https://backoff.com",
+				Config:   WebConfig{Retry: &WebConfigRetry{Max: 3, Backoff: 2 * time.Millisecond}},
+			},
+			{Endpoint: "^https://no-retry.com", Config: WebConfig{Retry: &WebConfigRetry{}}},
+		},
+	}
+	require.NoError(t, client.initRegexConfig())
+
+	for i := 0; i < len(tests); i++ {
+		tt := tests[i]
+		t.Run("Should "+tt.message, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.expected, client.retryConfig(tt.endpoint))
+		})
+	}
+}
+
+func TestWebValidAnchor(t *testing.T) {
+	t.Parallel()
+
+	text := strings.Repeat("Some text. ", 30)
+	tests := []struct {
+		message        string
+		body           string
+		anchor         string
+		isValid        bool
+		scriptRendered bool
+	}{
+		{
+			message: "find the anchor at the element id",
+			body:    `<h2 id="title">Title</h2>` + text,
+			anchor:  "title",
+			isValid: true,
+		},
+		{
+			message: "find the anchor at the name of a link",
+			body:    `<a name="title"></a>` + text,
+			anchor:  "title",
+			isValid: true,
+		},
+		{
+			message: "find the anchor with the GitHub prefix",
+			body:    `<h2><a id="user-content-title" href="#title"></a>Title</h2>` + text,
+			anchor:  "title",
+			isValid: true,
+		},
+		{
+			message: "not find the anchor at a link to it",
+			body:    `<a href="#title">` + text + `</a>`,
+			anchor:  "title",
+			isValid: false,
+		},
+		{
+			message: "not find the anchor at the name of other elements",
+			body:    `<input name="title">` + text,
+			anchor:  "title",
+			isValid: false,
+		},
+		{
+			message:        "detect the pages rendered by scripts",
+			body:           `<div id="root"></div><noscript>Enable JavaScript.</noscript><script>render()</script>`,
+			anchor:         "title",
+			isValid:        false,
+			scriptRendered: true,
+		},
+	}
+
+	for i := 0; i < len(tests); i++ {
+		tt := tests[i]
+		t.Run("Should "+tt.message, func(t *testing.T) {
+			t.Parallel()
+
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader(tt.body))
+			require.NoError(t, err)
+
+			var client Web
+			require.Equal(t, tt.isValid, client.validAnchor(doc, tt.anchor))
+			require.Equal(t, tt.scriptRendered, client.scriptRendered(doc))
+		})
+	}
+}
+
+func TestWebPagePool(t *testing.T) {
+	t.Parallel()
+
+	var created int
+	create := func() (*rod.Page, error) {
+		created++
+		return &rod.Page{}, nil
+	}
+
+	pool := newWebPagePool(2)
+	first, err := pool.get(context.Background(), create)
+	require.NoError(t, err)
+	second, err := pool.get(context.Background(), create)
+	require.NoError(t, err)
+	require.Equal(t, 2, created)
+
+	ctx, ctxCancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer ctxCancel()
+	_, err = pool.get(ctx, create)
+	require.Error(t, err, "expected the pool to be exhausted")
+
+	pool.put(first)
+	page, err := pool.get(context.Background(), create)
+	require.NoError(t, err)
+	require.Same(t, first, page)
+	require.Equal(t, 2, created)
+
+	pool.put(nil)
+	_, err = pool.get(context.Background(), func() (*rod.Page, error) {
+		return nil, errors.New("failed to create the page")
+	})
+	require.Error(t, err)
+	pool.put(second)
+
+	page, err = pool.get(context.Background(), create)
+	require.NoError(t, err)
+	require.Equal(t, 3, created)
+	require.NotSame(t, second, page)
+}
+
+func TestWebValidRedirect(t *testing.T) {
+	t.Parallel()
+
+	var serverURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		redirect := func(location string, statusCode int) {
+			w.Header().Set("location", location)
+			w.WriteHeader(statusCode)
+		}
+
+		switch r.URL.Path {
+		case "/valid":
+			_, err := w.Write([]byte(`<html><body><h1 id="section">Section</h1></body></html>`))
+			require.NoError(t, err)
+		case "/302":
+			redirect("/valid", http.StatusFound)
+		case "/301-chain":
+			redirect("/301", http.StatusMovedPermanently)
+		case "/301":
+			redirect("/valid", http.StatusMovedPermanently)
+		case "/301-cross-domain":
+			redirect(strings.Replace(serverURL, "127.0.0.1", "localhost", 1)+"/valid", http.StatusMovedPermanently)
+		case "/401":
+			w.WriteHeader(http.StatusUnauthorized)
+		default:
+			require.FailNow(t, "not expected to reach this point")
+		}
+	}))
+	defer server.Close()
+	serverURL = server.URL
+
+	crossDomain := true
+	tests := []struct {
+		message   string
+		path      string
+		config    WebConfig
+		overwrite []WebConfigOverwrite
+		isValid   bool
+		reason    service.Reason
+		redirects []service.Redirect
+	}{
+		{
+			message: "attest the URI as invalid because of a redirect not followed",
+			path:    "/302",
+			isValid: false,
+			reason:  service.ReasonRedirect,
+		},
+		{
+			message:   "attest the URI as valid after following a configured redirect",
+			path:      "/302",
+			config:    WebConfig{Redirect: WebConfigRedirect{StatusCodes: []int{http.StatusFound}}},
+			isValid:   true,
+			redirects: []service.Redirect{{Link: server.URL + "/valid", StatusCode: http.StatusFound}},
+		},
+		{
+			message: "attest the URI as valid and report the permanent redirects with the fragment",
+			path:    "/301-chain#section",
+			isValid: true,
+			reason:  service.ReasonPermanentRedirect,
+			redirects: []service.Redirect{
+				{Link: server.URL + "/301#section", StatusCode: http.StatusMovedPermanently},
+				{Link: server.URL + "/valid#section", StatusCode: http.StatusMovedPermanently},
+			},
+		},
+		{
+			message: "attest the URI as valid because of an accepted redirect status code",
+			path:    "/302",
+			config:  WebConfig{StatusCodes: []int{http.StatusOK, http.StatusFound}},
+			isValid: true,
+		},
+		{
+			message: "attest the URI as valid because of an accepted status code",
+			path:    "/401",
+			config:  WebConfig{StatusCodes: []int{http.StatusOK, http.StatusUnauthorized}},
+			isValid: true,
+		},
+		{
+			message: "attest the URI as invalid because of the amount of redirects",
+			path:    "/301-chain",
+			config:  WebConfig{Redirect: WebConfigRedirect{MaxHops: 1}},
+			isValid: false,
+			reason:  service.ReasonRedirect,
+		},
+		{
+			message: "attest the URI as invalid because of a cross-domain redirect",
+			path:    "/301-cross-domain",
+			config:  WebConfig{Redirect: WebConfigRedirect{CrossDomain: new(bool)}},
+			isValid: false,
+			reason:  service.ReasonRedirect,
+		},
+		{
+			message: "attest the URI as valid because of a cross-domain redirect allowed by the overwrite",
+			path:    "/301-cross-domain",
+			config:  WebConfig{Redirect: WebConfigRedirect{CrossDomain: new(bool)}},
+			overwrite: []WebConfigOverwrite{
+				{Endpoint: "cross-domain$", Config: WebConfig{Redirect: WebConfigRedirect{CrossDomain: &crossDomain}}},
+			},
+			isValid: true,
+			reason:  service.ReasonPermanentRedirect,
+			redirects: []service.Redirect{
+				{
+					Link:       strings.Replace(server.URL, "127.0.0.1", "localhost", 1) + "/valid",
+					StatusCode: http.StatusMovedPermanently,
+				},
+			},
+		},
+	}
+
+	for i := 0; i < len(tests); i++ {
+		tt := tests[i]
+		t.Run("Should "+tt.message, func(t *testing.T) {
+			client := Web{Config: tt.config, ConfigOverwrite: tt.overwrite}
+			require.NoError(t, client.initRegexConfig())
+			client.initHTTP()
+			client.initLimiter()
+
+			result, err := client.Valid(context.Background(), "", server.URL+tt.path)
+			require.NoError(t, err)
+			require.Equal(t, tt.isValid, result.Valid)
+			require.Equal(t, tt.reason, result.Reason)
+			require.Equal(t, tt.redirects, result.Redirects)
 		})
 	}
 }
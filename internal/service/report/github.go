@@ -0,0 +1,102 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"nitro/markdown-link-check/internal/service"
+)
+
+// GitHub reports the invalid entries as GitHub Actions workflow commands, so they're annotated at the pull request
+// diff. The annotations need the file path relative to the repository root, 'Workspace' is the repository root path
+// and it's usually the value of the 'GITHUB_WORKSPACE' environment variable.
+type GitHub struct {
+	Workspace string
+}
+
+// Report writes an error or warning command for each entry that needs attention grouped by file and a notice for each
+// fixed baseline entry.
+func (g GitHub) Report(w io.Writer, execution Execution) error {
+	var (
+		buf  bytes.Buffer
+		iter = aggregate(execution.Entries)
+	)
+	for {
+		key, entries, ok := iter()
+		if !ok {
+			break
+		}
+		if !hasFinding(entries) {
+			continue
+		}
+
+		fmt.Fprintf(&buf, "::group::%s\n", g.escapeData(relativePath(execution.Path, key)))
+		for _, entry := range entries {
+			if !finding(entry) {
+				continue
+			}
+			fmt.Fprintf(&buf, "::%s %s::%s\n", g.command(entry), g.properties(entry), g.escapeData(description(entry)))
+		}
+		fmt.Fprintln(&buf, "::endgroup::")
+	}
+
+	for _, entry := range execution.Fixed {
+		fmt.Fprintf(
+			&buf,
+			"::notice file=%s,title=Fixed baseline entry::%s\n",
+			g.escapeProperty(g.path(entry.Path)),
+			g.escapeData(fmt.Sprintf("The link '%s' is fixed and can be removed from the baseline", entry.Link)),
+		)
+	}
+
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("fail to write the report: %w", err)
+	}
+	return nil
+}
+
+func (g GitHub) properties(entry service.Entry) string {
+	properties := []string{fmt.Sprintf("file=%s", g.escapeProperty(g.path(entry.Path)))}
+	if entry.Line > 0 {
+		properties = append(
+			properties,
+			fmt.Sprintf("line=%d", entry.Line),
+			fmt.Sprintf("col=%d", entry.Column),
+		)
+	}
+	title := fmt.Sprintf("Invalid %s", kind(entry))
+	switch {
+	case entry.Reason == service.ReasonUndefinedReference:
+		title = "Undefined reference"
+	case entry.Reason == service.ReasonUnusedDefinition:
+		title = "Unused reference definition"
+	case entry.Errored:
+		k := string(kind(entry))
+		title = fmt.Sprintf("%s not verified", strings.ToUpper(k[:1])+k[1:])
+	case entry.Valid:
+		title = fmt.Sprintf("Outdated %s", kind(entry))
+	}
+	properties = append(properties, "title="+g.escapeProperty(title))
+	return strings.Join(properties, ",")
+}
+
+func (GitHub) command(entry service.Entry) string {
+	if warning(entry) {
+		return "warning"
+	}
+	return "error"
+}
+
+func (g GitHub) path(path string) string {
+	return workspacePath(g.Workspace, path)
+}
+
+func (GitHub) escapeData(value string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(value)
+}
+
+func (GitHub) escapeProperty(value string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(value)
+}
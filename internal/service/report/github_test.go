@@ -0,0 +1,155 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"nitro/markdown-link-check/internal/service"
+)
+
+func TestGitHubReport(t *testing.T) {
+	t.Parallel()
+
+	execution := Execution{
+		Path: "/workspace/docs",
+		Entries: []service.Entry{
+			{
+				Path:   "/workspace/docs/a.md",
+				Link:   "https://website.com",
+				Line:   1,
+				Column: 1,
+				Result: service.Result{Valid: true, Provider: "web"},
+			},
+			{
+				Path:   "/workspace/docs/a.md",
+				Link:   "b.md",
+				Line:   2,
+				Column: 3,
+				Result: service.Result{Provider: "file", Reason: service.ReasonNotFound, Message: "file 'b.md' not found"},
+			},
+			{
+				Path:   "/workspace/docs/a.md",
+				Link:   "logo.png",
+				Kind:   service.KindImage,
+				Line:   3,
+				Column: 1,
+				Result: service.Result{Errored: true, Severity: service.SeverityWarning, Provider: "file", Message: "fail"},
+			},
+			{
+				Path:   "/workspace/docs/a.md",
+				Link:   "https://old.com",
+				Line:   4,
+				Column: 1,
+				Result: service.Result{
+					Valid:    true,
+					Severity: service.SeverityWarning,
+					Provider: "web",
+					Reason:   service.ReasonPermanentRedirect,
+					Message:  "permanently redirected to 'https://new.com'",
+				},
+			},
+			{
+				Path:   "/workspace/docs/a.md",
+				Link:   "[missing]",
+				Text:   "text",
+				Line:   5,
+				Column: 1,
+				Result: service.Result{
+					Severity: service.SeverityError,
+					Provider: "markdown",
+					Reason:   service.ReasonUndefinedReference,
+					Message:  "reference '[missing]' is not defined",
+				},
+			},
+			{
+				Path:   "/workspace/docs/a.md",
+				Link:   "https://unused.com",
+				Text:   "unused",
+				Line:   6,
+				Column: 1,
+				Result: service.Result{
+					Severity: service.SeverityWarning,
+					Provider: "markdown",
+					Reason:   service.ReasonUnusedDefinition,
+					Message:  "definition '[unused]' is never used",
+				},
+			},
+			{
+				Path:   "/workspace/docs/c,d.md",
+				Link:   "https://website.com/100%",
+				Result: service.Result{Provider: "web", Reason: service.ReasonTimeout, Message: "first\nsecond"},
+			},
+			{
+				Path:   "/workspace/docs/c,d.md",
+				Link:   "https://website.com",
+				Line:   1,
+				Column: 1,
+				Result: service.Result{
+					Errored:  true,
+					Severity: service.SeverityWarning,
+					Provider: "web",
+					Reason:   service.ReasonErrored,
+					Message:  "fail",
+				},
+			},
+			{
+				Path:   "/workspace/docs/valid.md",
+				Link:   "a.md",
+				Line:   1,
+				Column: 1,
+				Result: service.Result{Valid: true, Provider: "file"},
+			},
+		},
+	}
+
+	tests := []struct {
+		message   string
+		workspace string
+		expected  string
+	}{
+		{
+			message:   "report the paths relative to the workspace",
+			workspace: "/workspace",
+			expected: `::group::a.md
+::error file=docs/a.md,line=2,col=3,title=Invalid link::The link 'b.md' is invalid: file 'b.md' not found
+::warning file=docs/a.md,line=3,col=1,title=Image not verified::The image 'logo.png' could not be verified: fail
+::warning file=docs/a.md,line=4,col=1,title=Outdated link::The link 'https://old.com' is outdated: permanently redirected to 'https://new.com'
+::error file=docs/a.md,line=5,col=1,title=Undefined reference::The reference '[missing]' is not defined
+::warning file=docs/a.md,line=6,col=1,title=Unused reference definition::The reference definition '[unused]' to 'https://unused.com' is never used
+::endgroup::
+::group::c,d.md
+::error file=docs/c%2Cd.md,title=Invalid link::The link 'https://website.com/100%25' is invalid: first%0Asecond
+::warning file=docs/c%2Cd.md,line=1,col=1,title=Link not verified::The link 'https://website.com' could not be verified: fail
+::endgroup::
+`,
+		},
+		{
+			message: "report the paths as they are without a workspace",
+			expected: `::group::a.md
+::error file=/workspace/docs/a.md,line=2,col=3,title=Invalid link::The link 'b.md' is invalid: file 'b.md' not found
+::warning file=/workspace/docs/a.md,line=3,col=1,title=Image not verified::The image 'logo.png' could not be verified: fail
+::warning file=/workspace/docs/a.md,line=4,col=1,title=Outdated link::The link 'https://old.com' is outdated: permanently redirected to 'https://new.com'
+::error file=/workspace/docs/a.md,line=5,col=1,title=Undefined reference::The reference '[missing]' is not defined
+::warning file=/workspace/docs/a.md,line=6,col=1,title=Unused reference definition::The reference definition '[unused]' to 'https://unused.com' is never used
+::endgroup::
+::group::c,d.md
+::error file=/workspace/docs/c%2Cd.md,title=Invalid link::The link 'https://website.com/100%25' is invalid: first%0Asecond
+::warning file=/workspace/docs/c%2Cd.md,line=1,col=1,title=Link not verified::The link 'https://website.com' could not be verified: fail
+::endgroup::
+`,
+		},
+	}
+
+	for i := 0; i < len(tests); i++ {
+		tt := tests[i]
+		t.Run("Should "+tt.message, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			require.NoError(t, GitHub{Workspace: tt.workspace}.Report(&buf, execution))
+			require.Equal(t, tt.expected, buf.String())
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+type jsonReport struct {
+	Entries []jsonEntry `json:"entries"`
+	Fixed   []jsonFixed `json:"fixed,omitempty"`
+	Summary jsonSummary `json:"summary"`
+}
+
+type jsonFixed struct {
+	File   string `json:"file"`
+	Link   string `json:"link"`
+	Reason string `json:"reason,omitempty"`
+}
+
+type jsonEntry struct {
+	File       string         `json:"file"`
+	Line       int            `json:"line"`
+	Column     int            `json:"column"`
+	Link       string         `json:"link"`
+	Kind       string         `json:"kind"`
+	Text       string         `json:"text,omitempty"`
+	Valid      bool           `json:"valid"`
+	Errored    bool           `json:"errored,omitempty"`
+	Baseline   bool           `json:"baseline,omitempty"`
+	Severity   string         `json:"severity,omitempty"`
+	Provider   string         `json:"provider"`
+	Reason     string         `json:"reason,omitempty"`
+	StatusCode int            `json:"statusCode,omitempty"`
+	Message    string         `json:"message,omitempty"`
+	Redirects  []jsonRedirect `json:"redirects,omitempty"`
+	Duration   float64        `json:"durationMs"`
+}
+
+type jsonRedirect struct {
+	Link       string `json:"link"`
+	StatusCode int    `json:"statusCode"`
+}
+
+type jsonSummary struct {
+	Files    int     `json:"files"`
+	Total    int     `json:"total"`
+	Valid    int     `json:"valid"`
+	Invalid  int     `json:"invalid"`
+	Errored  int     `json:"errored"`
+	Warnings int     `json:"warnings"`
+	Baseline int     `json:"baseline"`
+	Fixed    int     `json:"fixed"`
+	Duration float64 `json:"durationMs"`
+}
+
+// JSON reports all the entries and a summary of the execution in a machine readable format.
+type JSON struct{}
+
+// Report writes the JSON document.
+func (JSON) Report(w io.Writer, execution Execution) error {
+	sum := summarize(execution)
+	report := jsonReport{
+		Entries: make([]jsonEntry, 0, len(execution.Entries)),
+		Summary: jsonSummary{
+			Files:    sum.files,
+			Total:    sum.total,
+			Valid:    sum.valid,
+			Invalid:  sum.invalid,
+			Errored:  sum.errored,
+			Warnings: sum.warnings,
+			Baseline: sum.baseline,
+			Fixed:    len(execution.Fixed),
+			Duration: milliseconds(execution.Duration),
+		},
+	}
+
+	iter := aggregate(execution.Entries)
+	for {
+		key, entries, ok := iter()
+		if !ok {
+			break
+		}
+		for _, entry := range entries {
+			var redirects []jsonRedirect
+			for _, redirect := range entry.Redirects {
+				redirects = append(redirects, jsonRedirect{Link: redirect.Link, StatusCode: redirect.StatusCode})
+			}
+			report.Entries = append(report.Entries, jsonEntry{
+				File:       relativePath(execution.Path, key),
+				Line:       entry.Line,
+				Column:     entry.Column,
+				Link:       entry.Link,
+				Kind:       string(kind(entry)),
+				Text:       entry.Text,
+				Valid:      entry.Valid,
+				Errored:    entry.Errored,
+				Baseline:   entry.Baseline,
+				Severity:   string(entry.Severity),
+				Provider:   entry.Provider,
+				Reason:     string(entry.Reason),
+				StatusCode: entry.StatusCode,
+				Message:    entry.Message,
+				Redirects:  redirects,
+				Duration:   milliseconds(entry.Duration),
+			})
+		}
+	}
+
+	for _, entry := range execution.Fixed {
+		report.Fixed = append(report.Fixed, jsonFixed{
+			File:   relativePath(execution.Path, entry.Path),
+			Link:   entry.Link,
+			Reason: string(entry.Reason),
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return fmt.Errorf("fail to encode the report: %w", err)
+	}
+	return nil
+}
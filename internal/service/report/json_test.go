@@ -0,0 +1,190 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"nitro/markdown-link-check/internal/service"
+)
+
+func TestJSONReport(t *testing.T) {
+	t.Parallel()
+
+	execution := Execution{
+		Path:     "/docs",
+		Files:    []string{"/docs/a.md", "/docs/b.md", "/docs/c.md", "/docs/d.md"},
+		Duration: 1500 * time.Microsecond,
+		Entries: []service.Entry{
+			{
+				Path:   "/docs/b.md",
+				Link:   "https://website.com",
+				Line:   1,
+				Column: 2,
+				Result: service.Result{
+					Valid:      true,
+					Provider:   "web",
+					StatusCode: 200,
+					Redirects:  []service.Redirect{{Link: "https://www.website.com", StatusCode: 302}},
+					Duration:   time.Millisecond,
+				},
+			},
+			{
+				Path:   "/docs/a.md",
+				Link:   "missing.md",
+				Text:   "missing",
+				Line:   3,
+				Column: 4,
+				Result: service.Result{
+					Severity: service.SeverityError,
+					Provider: "file",
+					Reason:   service.ReasonNotFound,
+					Message:  "file 'missing.md' not found",
+				},
+			},
+			{
+				Path:   "/docs/a.md",
+				Link:   "unused.md",
+				Text:   "unused",
+				Line:   5,
+				Column: 1,
+				Result: service.Result{
+					Severity: service.SeverityWarning,
+					Provider: "markdown",
+					Reason:   service.ReasonUnusedDefinition,
+					Message:  "definition '[unused]' is never used",
+				},
+			},
+			{
+				Path:   "/docs/b.md",
+				Link:   "https://website.com/timeout",
+				Line:   2,
+				Column: 1,
+				Result: service.Result{
+					Errored:  true,
+					Severity: service.SeverityWarning,
+					Provider: "web",
+					Reason:   service.ReasonErrored,
+					Message:  "browser failure",
+				},
+			},
+			{
+				Path:   "/docs/c.md",
+				Link:   "known.md",
+				Line:   1,
+				Column: 1,
+				Result: service.Result{
+					Baseline: true,
+					Severity: service.SeverityError,
+					Provider: "file",
+					Reason:   service.ReasonNotFound,
+				},
+			},
+		},
+		Fixed: []service.Entry{
+			{Path: "/docs/c.md", Link: "fixed.md", Result: service.Result{Valid: true, Reason: service.ReasonNotFound}},
+		},
+	}
+
+	expected := `{
+  "entries": [
+    {
+      "file": "a.md",
+      "line": 3,
+      "column": 4,
+      "link": "missing.md",
+      "kind": "link",
+      "text": "missing",
+      "valid": false,
+      "severity": "error",
+      "provider": "file",
+      "reason": "not-found",
+      "message": "file 'missing.md' not found",
+      "durationMs": 0
+    },
+    {
+      "file": "a.md",
+      "line": 5,
+      "column": 1,
+      "link": "unused.md",
+      "kind": "link",
+      "text": "unused",
+      "valid": false,
+      "severity": "warning",
+      "provider": "markdown",
+      "reason": "unused-definition",
+      "message": "definition '[unused]' is never used",
+      "durationMs": 0
+    },
+    {
+      "file": "b.md",
+      "line": 1,
+      "column": 2,
+      "link": "https://website.com",
+      "kind": "link",
+      "valid": true,
+      "provider": "web",
+      "statusCode": 200,
+      "redirects": [
+        {
+          "link": "https://www.website.com",
+          "statusCode": 302
+        }
+      ],
+      "durationMs": 1
+    },
+    {
+      "file": "b.md",
+      "line": 2,
+      "column": 1,
+      "link": "https://website.com/timeout",
+      "kind": "link",
+      "valid": false,
+      "errored": true,
+      "severity": "warning",
+      "provider": "web",
+      "reason": "errored",
+      "message": "browser failure",
+      "durationMs": 0
+    },
+    {
+      "file": "c.md",
+      "line": 1,
+      "column": 1,
+      "link": "known.md",
+      "kind": "link",
+      "valid": false,
+      "baseline": true,
+      "severity": "error",
+      "provider": "file",
+      "reason": "not-found",
+      "durationMs": 0
+    }
+  ],
+  "fixed": [
+    {
+      "file": "c.md",
+      "link": "fixed.md",
+      "reason": "not-found"
+    }
+  ],
+  "summary": {
+    "files": 4,
+    "total": 5,
+    "valid": 1,
+    "invalid": 2,
+    "errored": 1,
+    "warnings": 2,
+    "baseline": 1,
+    "fixed": 1,
+    "durationMs": 1.5
+  }
+}
+`
+
+	var buf bytes.Buffer
+	require.NoError(t, JSON{}.Report(&buf, execution))
+	require.Equal(t, expected, buf.String())
+}
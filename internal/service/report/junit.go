@@ -0,0 +1,139 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+
+	"nitro/markdown-link-check/internal/service"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Errors   int             `xml:"errors,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr"`
+	Line      int           `xml:"line,attr,omitempty"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Error     *junitFailure `xml:"error,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// JUnit reports the execution in the JUnit XML format. Each Markdown file is a test suite and each link is a test case.
+// The invalid entries are failures, the errored entries are errors and the warnings are written to the test output.
+type JUnit struct{}
+
+// Report writes the JUnit XML document.
+func (j JUnit) Report(w io.Writer, execution Execution) error {
+	entries := make(map[string][]service.Entry, len(execution.Files))
+	iter := aggregate(execution.Entries)
+	for {
+		key, fileEntries, ok := iter()
+		if !ok {
+			break
+		}
+		entries[key] = fileEntries
+	}
+
+	report := junitTestSuites{
+		Name:   "markdown-link-check",
+		Time:   j.seconds(execution.Duration),
+		Suites: make([]junitTestSuite, 0, len(execution.Files)),
+	}
+	for _, file := range execution.Files {
+		suite := j.suite(relativePath(execution.Path, file), entries[file])
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Errors += suite.Errors
+		report.Suites = append(report.Suites, suite)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("fail to write the report header: %w", err)
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return fmt.Errorf("fail to encode the report: %w", err)
+	}
+	if _, err := io.WriteString(w, "\n"); err != nil {
+		return fmt.Errorf("fail to write the report: %w", err)
+	}
+	return nil
+}
+
+func (j JUnit) suite(path string, entries []service.Entry) junitTestSuite {
+	var (
+		duration time.Duration
+		suite    = junitTestSuite{Name: path, Tests: len(entries), Cases: make([]junitTestCase, 0, len(entries))}
+	)
+	for _, entry := range entries {
+		duration += entry.Duration
+		testCase := junitTestCase{
+			Name:      entry.Link,
+			ClassName: path,
+			File:      path,
+			Line:      entry.Line,
+			Time:      j.seconds(entry.Duration),
+		}
+		failure := &junitFailure{
+			Message: message(entry),
+			Type:    string(entry.Reason),
+			Text:    fmt.Sprintf("%s: %s %s (%s)", j.location(path, entry), kind(entry), entry.Link, entry.Provider),
+		}
+		switch {
+		case !finding(entry):
+		case warning(entry):
+			testCase.SystemOut = fmt.Sprintf("warning: %s", failure.Text)
+			if failure.Message != "" {
+				testCase.SystemOut += ": " + failure.Message
+			}
+		case entry.Errored:
+			suite.Errors++
+			testCase.Error = failure
+		default:
+			suite.Failures++
+			testCase.Failure = failure
+		}
+		suite.Cases = append(suite.Cases, testCase)
+	}
+	suite.Time = j.seconds(duration)
+	return suite
+}
+
+func (JUnit) location(path string, entry service.Entry) string {
+	if entry.Line == 0 {
+		return path
+	}
+	return fmt.Sprintf("%s:%d:%d", path, entry.Line, entry.Column)
+}
+
+func (JUnit) seconds(duration time.Duration) string {
+	return fmt.Sprintf("%.3f", duration.Seconds())
+}
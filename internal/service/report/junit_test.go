@@ -0,0 +1,79 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"nitro/markdown-link-check/internal/service"
+)
+
+func TestJUnitReport(t *testing.T) {
+	t.Parallel()
+
+	execution := Execution{
+		Path:     "/docs",
+		Files:    []string{"/docs/a.md", "/docs/empty.md"},
+		Duration: 2 * time.Second,
+		Entries: []service.Entry{
+			{
+				Path:   "/docs/a.md",
+				Link:   "https://website.com",
+				Line:   1,
+				Column: 1,
+				Result: service.Result{Valid: true, Provider: "web", Duration: time.Second},
+			},
+			{
+				Path:   "/docs/a.md",
+				Link:   "b.md",
+				Line:   2,
+				Column: 3,
+				Result: service.Result{Provider: "file", Reason: service.ReasonNotFound, Message: "file 'b.md' not found"},
+			},
+			{
+				Path:   "/docs/a.md",
+				Link:   "https://website.com/error",
+				Line:   3,
+				Column: 1,
+				Result: service.Result{Errored: true, Provider: "web", Reason: service.ReasonErrored, Message: "fail"},
+			},
+			{
+				Path:   "/docs/a.md",
+				Link:   "https://website.com/warning",
+				Line:   4,
+				Column: 1,
+				Result: service.Result{
+					Errored:  true,
+					Severity: service.SeverityWarning,
+					Provider: "web",
+					Reason:   service.ReasonErrored,
+					Message:  "fail",
+				},
+			},
+		},
+	}
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="markdown-link-check" tests="4" failures="1" errors="1" time="2.000">
+  <testsuite name="a.md" tests="4" failures="1" errors="1" time="1.000">
+    <testcase name="https://website.com" classname="a.md" file="a.md" line="1" time="1.000"></testcase>
+    <testcase name="b.md" classname="a.md" file="a.md" line="2" time="0.000">
+      <failure message="file &#39;b.md&#39; not found" type="not-found">a.md:2:3: link b.md (file)</failure>
+    </testcase>
+    <testcase name="https://website.com/error" classname="a.md" file="a.md" line="3" time="0.000">
+      <error message="fail" type="errored">a.md:3:1: link https://website.com/error (web)</error>
+    </testcase>
+    <testcase name="https://website.com/warning" classname="a.md" file="a.md" line="4" time="0.000">
+      <system-out>warning: a.md:4:1: link https://website.com/warning (web): fail</system-out>
+    </testcase>
+  </testsuite>
+  <testsuite name="empty.md" tests="0" failures="0" errors="0" time="0.000"></testsuite>
+</testsuites>
+`
+
+	var buf bytes.Buffer
+	require.NoError(t, JUnit{}.Report(&buf, execution))
+	require.Equal(t, expected, buf.String())
+}
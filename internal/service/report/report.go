@@ -0,0 +1,183 @@
+package report
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"nitro/markdown-link-check/internal/service"
+)
+
+// Execution holds the information about the execution to be reported. 'Path' is the directory processed and it's used
+// to report the files relative to it. 'Files' has all the Markdown files processed, including the ones without links.
+// 'Fixed' has the baseline entries that are not invalid anymore.
+type Execution struct {
+	Path     string
+	Files    []string
+	Entries  []service.Entry
+	Fixed    []service.Entry
+	Duration time.Duration
+}
+
+// summary of the execution. 'invalid' has the entries with the error severity that were not errored, the entries with
+// the warning severity are only counted at 'warnings'.
+type summary struct {
+	files    int
+	total    int
+	valid    int
+	invalid  int
+	errored  int
+	warnings int
+	baseline int
+}
+
+func summarize(execution Execution) summary {
+	result := summary{files: len(execution.Files), total: len(execution.Entries)}
+	for _, entry := range execution.Entries {
+		switch {
+		case entry.Valid:
+			result.valid++
+		case entry.Errored:
+			result.errored++
+		case entry.Severity == service.SeverityError:
+			result.invalid++
+		}
+		if warning(entry) {
+			result.warnings++
+		}
+		if entry.Baseline {
+			result.baseline++
+		}
+	}
+	return result
+}
+
+// aggregate the entries by file. The files are returned in lexical order and the entries are sorted by position.
+func aggregate(entries []service.Entry) func() (string, []service.Entry, bool) {
+	var (
+		keys   = make([]string, 0, len(entries))
+		result = make(map[string][]service.Entry)
+	)
+
+	for _, entry := range entries {
+		if _, ok := result[entry.Path]; !ok {
+			keys = append(keys, entry.Path)
+			result[entry.Path] = make([]service.Entry, 0)
+		}
+		result[entry.Path] = append(result[entry.Path], entry)
+	}
+	sort.Strings(keys)
+
+	var index = 0
+	return func() (string, []service.Entry, bool) {
+		if index >= len(keys) {
+			return "", nil, false
+		}
+		key := keys[index]
+		index++
+		entries := result[key]
+		sort.Stable(serviceEntrySort(entries))
+		return key, entries, true
+	}
+}
+
+func hasFinding(entries []service.Entry) bool {
+	for _, entry := range entries {
+		if finding(entry) {
+			return true
+		}
+	}
+	return false
+}
+
+// finding returns true if the entry needs attention and should be reported. The entries accepted by the baseline are
+// known and not reported.
+func finding(entry service.Entry) bool {
+	return (!entry.Valid || (entry.Severity != "")) && !entry.Baseline
+}
+
+// kind returns the kind of the resource referenced by the entry.
+func kind(entry service.Entry) service.Kind {
+	if entry.Kind == "" {
+		return service.KindLink
+	}
+	return entry.Kind
+}
+
+func warning(entry service.Entry) bool {
+	return entry.Severity == service.SeverityWarning
+}
+
+func relativePath(dirPath, path string) string {
+	if !strings.HasSuffix(dirPath, "/") {
+		dirPath += "/"
+	}
+	return strings.TrimPrefix(path, dirPath)
+}
+
+// workspacePath returns the file path relative to the workspace when possible, with forward slashes.
+func workspacePath(workspace, path string) string {
+	if workspace != "" {
+		absPath, err := filepath.Abs(path)
+		if err == nil {
+			if relPath, err := filepath.Rel(workspace, absPath); err == nil && !strings.HasPrefix(relPath, "..") {
+				path = relPath
+			}
+		}
+	}
+	return filepath.ToSlash(filepath.Clean(path))
+}
+
+// description returns the sentence that describes the finding of the entry, with the result message when present. The
+// broken references are described by themselves, their destination is never verified.
+func description(entry service.Entry) string {
+	text := fmt.Sprintf("The %s '%s' is invalid", kind(entry), entry.Link)
+	switch {
+	case entry.Reason == service.ReasonUndefinedReference:
+		return fmt.Sprintf("The reference '%s' is not defined", entry.Link)
+	case entry.Reason == service.ReasonUnusedDefinition:
+		return fmt.Sprintf("The reference definition '[%s]' to '%s' is never used", entry.Text, entry.Link)
+	case entry.Errored:
+		text = fmt.Sprintf("The %s '%s' could not be verified", kind(entry), entry.Link)
+	case entry.Valid:
+		text = fmt.Sprintf("The %s '%s' is outdated", kind(entry), entry.Link)
+	}
+	if msg := message(entry); msg != "" {
+		text = fmt.Sprintf("%s: %s", text, msg)
+	}
+	return text
+}
+
+// message returns the most descriptive information about the entry result.
+func message(entry service.Entry) string {
+	if entry.Message != "" {
+		return entry.Message
+	}
+	return string(entry.Reason)
+}
+
+func milliseconds(duration time.Duration) float64 {
+	return float64(duration) / float64(time.Millisecond)
+}
+
+type serviceEntrySort []service.Entry
+
+func (s serviceEntrySort) Len() int {
+	return len(s)
+}
+
+func (s serviceEntrySort) Swap(i, j int) {
+	s[i], s[j] = s[j], s[i]
+}
+
+func (s serviceEntrySort) Less(i, j int) bool {
+	if s[i].Line != s[j].Line {
+		return s[i].Line < s[j].Line
+	}
+	if s[i].Column != s[j].Column {
+		return s[i].Column < s[j].Column
+	}
+	return s[i].Link < s[j].Link
+}
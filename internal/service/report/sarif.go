@@ -0,0 +1,253 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"nitro/markdown-link-check/internal/service"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+
+	sarifLevelError   = "error"
+	sarifLevelWarning = "warning"
+)
+
+type sarifReport struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string                 `json:"id"`
+	Name                 string                 `json:"name"`
+	ShortDescription     sarifMessage           `json:"shortDescription"`
+	DefaultConfiguration sarifRuleConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifRuleConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+// Index of the rules at 'sarifRules'.
+const (
+	sarifRuleMissingFile = iota
+	sarifRuleMissingAnchor
+	sarifRuleHTTPError
+	sarifRuleUnknownEmailDomain
+	sarifRuleGitHubResourceMissing
+	sarifRuleInvalidLink
+	sarifRuleUnverifiedLink
+	sarifRuleMissingMedia
+	sarifRuleBrokenReference
+	sarifRulePermanentRedirect
+)
+
+// The rules are the failure categories and the results reference them by index. The default level is error, unless
+// the rule is only reported as warning.
+var sarifRules = []sarifRule{ // nolint: gochecknoglobals
+	sarifRuleMissingFile: {
+		ID:               "missing-file",
+		Name:             "MissingFile",
+		ShortDescription: sarifMessage{Text: "The link points to a file or directory that does not exist."},
+	},
+	sarifRuleMissingAnchor: {
+		ID:               "missing-anchor",
+		Name:             "MissingAnchor",
+		ShortDescription: sarifMessage{Text: "The link points to an anchor that does not exist."},
+	},
+	sarifRuleHTTPError: {
+		ID:               "http-error",
+		Name:             "HTTPError",
+		ShortDescription: sarifMessage{Text: "The link points to a web page that could not be retrieved."},
+	},
+	sarifRuleUnknownEmailDomain: {
+		ID:               "unknown-email-domain",
+		Name:             "UnknownEmailDomain",
+		ShortDescription: sarifMessage{Text: "The email address has a domain that does not accept emails."},
+	},
+	sarifRuleGitHubResourceMissing: {
+		ID:               "github-resource-missing",
+		Name:             "GitHubResourceMissing",
+		ShortDescription: sarifMessage{Text: "The link points to a GitHub resource that does not exist."},
+	},
+	sarifRuleInvalidLink: {
+		ID:               "invalid-link",
+		Name:             "InvalidLink",
+		ShortDescription: sarifMessage{Text: "The link is invalid."},
+	},
+	sarifRuleUnverifiedLink: {
+		ID:               "unverified-link",
+		Name:             "UnverifiedLink",
+		ShortDescription: sarifMessage{Text: "The link could not be verified because of an error."},
+	},
+	sarifRuleMissingMedia: {
+		ID:               "missing-media",
+		Name:             "MissingMedia",
+		ShortDescription: sarifMessage{Text: "The image or media source does not exist or could not be retrieved."},
+	},
+	sarifRuleBrokenReference: {
+		ID:               "broken-reference",
+		Name:             "BrokenReference",
+		ShortDescription: sarifMessage{Text: "The reference has no definition or the definition is never used."},
+	},
+	sarifRulePermanentRedirect: {
+		ID:                   "permanent-redirect",
+		Name:                 "PermanentRedirect",
+		ShortDescription:     sarifMessage{Text: "The link is permanently redirected and should point to the final URL."},
+		DefaultConfiguration: sarifRuleConfiguration{Level: sarifLevelWarning},
+	},
+}
+
+// SARIF reports the invalid, errored and warning entries in the Static Analysis Results Interchange Format, version 2.1.0.
+// The code scanning tools need the file path relative to the repository root, 'Workspace' is the repository root path.
+type SARIF struct {
+	Workspace string
+}
+
+// Report writes the SARIF document.
+func (s SARIF) Report(w io.Writer, execution Execution) error {
+	rules := make([]sarifRule, 0, len(sarifRules))
+	for _, rule := range sarifRules {
+		if rule.DefaultConfiguration.Level == "" {
+			rule.DefaultConfiguration.Level = sarifLevelError
+		}
+		rules = append(rules, rule)
+	}
+
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:           "markdown-link-check",
+				InformationURI: "https://github.com/Nitro/markdown-link-check",
+				Rules:          rules,
+			},
+		},
+		Results: make([]sarifResult, 0),
+	}
+
+	iter := aggregate(execution.Entries)
+	for {
+		key, entries, ok := iter()
+		if !ok {
+			break
+		}
+		for _, entry := range entries {
+			if !finding(entry) {
+				continue
+			}
+			run.Results = append(run.Results, s.result(workspacePath(s.Workspace, key), entry))
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	report := sarifReport{Schema: sarifSchema, Version: sarifVersion, Runs: []sarifRun{run}}
+	if err := encoder.Encode(report); err != nil {
+		return fmt.Errorf("fail to encode the report: %w", err)
+	}
+	return nil
+}
+
+func (s SARIF) result(path string, entry service.Entry) sarifResult {
+	location := sarifLocation{
+		PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: path}},
+	}
+	if entry.Line > 0 {
+		location.PhysicalLocation.Region = &sarifRegion{StartLine: entry.Line, StartColumn: entry.Column}
+	}
+
+	level := sarifLevelError
+	if warning(entry) {
+		level = sarifLevelWarning
+	}
+
+	index := s.ruleIndex(entry)
+	return sarifResult{
+		RuleID:    sarifRules[index].ID,
+		RuleIndex: index,
+		Level:     level,
+		Message:   sarifMessage{Text: description(entry) + "."},
+		Locations: []sarifLocation{location},
+	}
+}
+
+func (SARIF) ruleIndex(entry service.Entry) int {
+	if entry.Errored {
+		return sarifRuleUnverifiedLink
+	}
+	if (entry.Reason == service.ReasonUndefinedReference) || (entry.Reason == service.ReasonUnusedDefinition) {
+		return sarifRuleBrokenReference
+	}
+	if entry.Reason == service.ReasonPermanentRedirect {
+		return sarifRulePermanentRedirect
+	}
+	if kind(entry) != service.KindLink {
+		return sarifRuleMissingMedia
+	}
+	if entry.Reason == service.ReasonAnchorMissing {
+		return sarifRuleMissingAnchor
+	}
+
+	switch entry.Provider {
+	case "file":
+		return sarifRuleMissingFile
+	case "web":
+		return sarifRuleHTTPError
+	case "email":
+		return sarifRuleUnknownEmailDomain
+	case "github":
+		return sarifRuleGitHubResourceMissing
+	default:
+		return sarifRuleInvalidLink
+	}
+}
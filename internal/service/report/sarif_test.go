@@ -0,0 +1,203 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"nitro/markdown-link-check/internal/service"
+)
+
+func TestSARIFReport(t *testing.T) {
+	t.Parallel()
+
+	execution := Execution{
+		Path: "/docs",
+		Entries: []service.Entry{
+			{Path: "/docs/a.md", Link: "https://website.com", Result: service.Result{Valid: true, Provider: "web"}},
+			{
+				Path:   "/docs/a.md",
+				Link:   "b.md#anchor",
+				Line:   3,
+				Column: 4,
+				Result: service.Result{Provider: "file", Reason: service.ReasonAnchorMissing},
+			},
+			{
+				Path:   "/docs/a.md",
+				Link:   "img/logo.png",
+				Kind:   service.KindImage,
+				Line:   5,
+				Column: 3,
+				Result: service.Result{Provider: "file", Reason: service.ReasonNotFound},
+			},
+			{
+				Path:   "/docs/folder/c.md",
+				Link:   "https://website.com/404",
+				Line:   1,
+				Column: 1,
+				Result: service.Result{Provider: "web", Reason: service.ReasonNotFound, Message: "status code 404"},
+			},
+			{
+				Path:   "/docs/folder/c.md",
+				Link:   "mailto:someone@unknown.com",
+				Result: service.Result{Provider: "email", Reason: service.ReasonUnknownDomain},
+			},
+			{
+				Path:   "/docs/folder/c.md",
+				Link:   "[missing]",
+				Line:   3,
+				Column: 8,
+				Result: service.Result{
+					Severity: service.SeverityError,
+					Provider: "markdown",
+					Reason:   service.ReasonUndefinedReference,
+					Message:  "reference '[missing]' is not defined",
+				},
+			},
+			{
+				Path:   "/docs/folder/c.md",
+				Link:   "https://website.com/old",
+				Line:   4,
+				Column: 1,
+				Result: service.Result{
+					Valid:    true,
+					Severity: service.SeverityWarning,
+					Provider: "web",
+					Reason:   service.ReasonPermanentRedirect,
+					Message:  "permanently redirected to 'https://website.com/new'",
+				},
+			},
+			{
+				Path:   "/docs/folder/c.md",
+				Link:   "https://website.com/error",
+				Line:   2,
+				Column: 1,
+				Result: service.Result{
+					Errored:  true,
+					Severity: service.SeverityWarning,
+					Provider: "web",
+					Reason:   service.ReasonErrored,
+					Message:  "fail",
+				},
+			},
+			{
+				Path:   "/docs/folder/c.md",
+				Link:   "https://website.com/unused",
+				Text:   "unused",
+				Line:   5,
+				Column: 2,
+				Result: service.Result{
+					Severity: service.SeverityWarning,
+					Provider: "markdown",
+					Reason:   service.ReasonUnusedDefinition,
+					Message:  "definition '[unused]' is never used",
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, SARIF{Workspace: "/"}.Report(&buf, execution))
+
+	var report sarifReport
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+	require.Equal(t, sarifVersion, report.Version)
+	require.Len(t, report.Runs, 1)
+	require.Len(t, report.Runs[0].Tool.Driver.Rules, len(sarifRules))
+	for i, rule := range report.Runs[0].Tool.Driver.Rules {
+		level := sarifLevelError
+		if i == sarifRulePermanentRedirect {
+			level = sarifLevelWarning
+		}
+		require.Equal(t, level, rule.DefaultConfiguration.Level, rule.ID)
+	}
+
+	expected := []sarifResult{
+		{
+			RuleID:    "missing-anchor",
+			RuleIndex: sarifRuleMissingAnchor,
+			Level:     sarifLevelError,
+			Message:   sarifMessage{Text: "The link 'b.md#anchor' is invalid: anchor-missing."},
+			Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: "docs/a.md"},
+				Region:           &sarifRegion{StartLine: 3, StartColumn: 4},
+			}}},
+		},
+		{
+			RuleID:    "missing-media",
+			RuleIndex: sarifRuleMissingMedia,
+			Level:     sarifLevelError,
+			Message:   sarifMessage{Text: "The image 'img/logo.png' is invalid: not-found."},
+			Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: "docs/a.md"},
+				Region:           &sarifRegion{StartLine: 5, StartColumn: 3},
+			}}},
+		},
+		{
+			RuleID:    "unknown-email-domain",
+			RuleIndex: sarifRuleUnknownEmailDomain,
+			Level:     sarifLevelError,
+			Message:   sarifMessage{Text: "The link 'mailto:someone@unknown.com' is invalid: unknown-domain."},
+			Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: "docs/folder/c.md"},
+			}}},
+		},
+		{
+			RuleID:    "http-error",
+			RuleIndex: sarifRuleHTTPError,
+			Level:     sarifLevelError,
+			Message:   sarifMessage{Text: "The link 'https://website.com/404' is invalid: status code 404."},
+			Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: "docs/folder/c.md"},
+				Region:           &sarifRegion{StartLine: 1, StartColumn: 1},
+			}}},
+		},
+		{
+			RuleID:    "unverified-link",
+			RuleIndex: sarifRuleUnverifiedLink,
+			Level:     sarifLevelWarning,
+			Message:   sarifMessage{Text: "The link 'https://website.com/error' could not be verified: fail."},
+			Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: "docs/folder/c.md"},
+				Region:           &sarifRegion{StartLine: 2, StartColumn: 1},
+			}}},
+		},
+		{
+			RuleID:    "broken-reference",
+			RuleIndex: sarifRuleBrokenReference,
+			Level:     sarifLevelError,
+			Message:   sarifMessage{Text: "The reference '[missing]' is not defined."},
+			Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: "docs/folder/c.md"},
+				Region:           &sarifRegion{StartLine: 3, StartColumn: 8},
+			}}},
+		},
+		{
+			RuleID:    "permanent-redirect",
+			RuleIndex: sarifRulePermanentRedirect,
+			Level:     sarifLevelWarning,
+			Message: sarifMessage{
+				Text: "The link 'https://website.com/old' is outdated: permanently redirected to 'https://website.com/new'.",
+			},
+			Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: "docs/folder/c.md"},
+				Region:           &sarifRegion{StartLine: 4, StartColumn: 1},
+			}}},
+		},
+		{
+			RuleID:    "broken-reference",
+			RuleIndex: sarifRuleBrokenReference,
+			Level:     sarifLevelWarning,
+			Message: sarifMessage{
+				Text: "The reference definition '[unused]' to 'https://website.com/unused' is never used.",
+			},
+			Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: "docs/folder/c.md"},
+				Region:           &sarifRegion{StartLine: 5, StartColumn: 2},
+			}}},
+		},
+	}
+	require.Equal(t, expected, report.Runs[0].Results)
+}
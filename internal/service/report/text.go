@@ -0,0 +1,99 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/logrusorgru/aurora"
+
+	"nitro/markdown-link-check/internal/service"
+)
+
+// Text reports the invalid, errored and warning entries in a human readable format. The baseline entries that are
+// fixed are reported at the end.
+type Text struct {
+	Color bool
+}
+
+// Report writes the invalid entries grouped by file.
+func (t Text) Report(w io.Writer, execution Execution) error {
+	var (
+		buf  bytes.Buffer
+		au   = aurora.NewAurora(t.Color)
+		iter = aggregate(execution.Entries)
+	)
+	for {
+		key, entries, ok := iter()
+		if !ok {
+			break
+		}
+		if !hasFinding(entries) {
+			continue
+		}
+
+		fmt.Fprint(&buf, au.Bold(relativePath(execution.Path, key)))
+		for _, entry := range entries {
+			if !finding(entry) {
+				continue
+			}
+			fmt.Fprintf(
+				&buf,
+				"\n%s %s %s %s",
+				au.Bold(au.Gray(24, "-")),
+				au.Gray(18, t.location(execution.Path, entry)),
+				entry.Link,
+				au.Gray(12, t.reason(entry)),
+			)
+		}
+		fmt.Fprintf(&buf, "\n\n")
+	}
+
+	if len(execution.Fixed) > 0 {
+		fmt.Fprint(&buf, au.Bold("Fixed baseline entries"))
+		for _, entry := range execution.Fixed {
+			fmt.Fprintf(
+				&buf,
+				"\n%s %s %s",
+				au.Bold(au.Gray(24, "-")),
+				au.Gray(18, relativePath(execution.Path, entry.Path)),
+				entry.Link,
+			)
+		}
+		fmt.Fprintf(&buf, "\n\n")
+	}
+
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("fail to write the report: %w", err)
+	}
+	return nil
+}
+
+// location returns the entry position at the format 'file:line:column'.
+func (Text) location(path string, entry service.Entry) string {
+	file := relativePath(path, entry.Path)
+	if entry.Line == 0 {
+		return file
+	}
+	return fmt.Sprintf("%s:%d:%d", file, entry.Line, entry.Column)
+}
+
+func (Text) reason(entry service.Entry) string {
+	labels := []string{entry.Provider}
+	if kind(entry) != service.KindLink {
+		labels = append(labels, string(kind(entry)))
+	}
+	if entry.Errored {
+		labels = append(labels, "errored")
+	}
+	if warning(entry) {
+		labels = append(labels, "warning")
+	}
+
+	label := strings.Join(labels, ", ")
+	if msg := message(entry); msg != "" {
+		return fmt.Sprintf("(%s: %s)", label, msg)
+	}
+	return fmt.Sprintf("(%s)", label)
+}
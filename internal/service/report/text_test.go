@@ -0,0 +1,126 @@
+package report
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"nitro/markdown-link-check/internal/service"
+)
+
+func TestTextReport(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		message  string
+		entries  []service.Entry
+		fixed    []service.Entry
+		expected string
+	}{
+		{
+			message:  "report nothing when all the entries are valid",
+			entries:  []service.Entry{{Path: "/docs/a.md", Link: "b.md", Result: service.Result{Valid: true}}},
+			expected: "",
+		},
+		{
+			message: "report the invalid entries grouped by file and sorted by position",
+			entries: []service.Entry{
+				{Path: "/docs/b.md", Link: "c.md", Line: 1, Column: 1, Result: service.Result{Provider: "file"}},
+				{Path: "/docs/a.md", Link: "https://website.com", Line: 4, Column: 2, Result: service.Result{Valid: true}},
+				{
+					Path:   "/docs/a.md",
+					Link:   "#anchor",
+					Line:   2,
+					Column: 8,
+					Result: service.Result{Provider: "file", Reason: service.ReasonAnchorMissing},
+				},
+				{
+					Path:   "/docs/a.md",
+					Link:   "https://website.com/404",
+					Result: service.Result{Provider: "web", Message: "status code 404 (Not Found)"},
+				},
+			},
+			expected: "a.md\n" +
+				"- a.md https://website.com/404 (web: status code 404 (Not Found))\n" +
+				"- a.md:2:8 #anchor (file: anchor-missing)\n\n" +
+				"b.md\n" +
+				"- b.md:1:1 c.md (file)\n\n",
+		},
+		{
+			message: "report the kind of the images and media sources",
+			entries: []service.Entry{
+				{
+					Path:   "/docs/a.md",
+					Link:   "logo.png",
+					Kind:   service.KindImage,
+					Line:   1,
+					Column: 3,
+					Result: service.Result{Provider: "file", Message: "file 'logo.png' not found"},
+				},
+				{
+					Path:   "/docs/a.md",
+					Link:   "https://website.com/demo.mp4",
+					Kind:   service.KindMedia,
+					Line:   2,
+					Column: 1,
+					Result: service.Result{Provider: "web"},
+				},
+			},
+			expected: "a.md\n" +
+				"- a.md:1:3 logo.png (file, image: file 'logo.png' not found)\n" +
+				"- a.md:2:1 https://website.com/demo.mp4 (web, media)\n\n",
+		},
+		{
+			message: "report the fixed baseline entries and ignore the known ones",
+			entries: []service.Entry{
+				{
+					Path:   "/docs/a.md",
+					Link:   "known.md",
+					Result: service.Result{Baseline: true, Severity: service.SeverityError, Provider: "file"},
+				},
+			},
+			fixed: []service.Entry{{Path: "/docs/a.md", Link: "fixed.md", Result: service.Result{Valid: true}}},
+			expected: "Fixed baseline entries\n" +
+				"- a.md fixed.md\n\n",
+		},
+		{
+			message: "report the errored and warning entries",
+			entries: []service.Entry{
+				{
+					Path:   "/docs/a.md",
+					Link:   "https://website.com/error",
+					Line:   1,
+					Column: 1,
+					Result: service.Result{Errored: true, Provider: "web", Message: "fail"},
+				},
+				{
+					Path:   "/docs/a.md",
+					Link:   "https://website.com/warning",
+					Line:   2,
+					Column: 1,
+					Result: service.Result{
+						Errored:  true,
+						Severity: service.SeverityWarning,
+						Provider: "web",
+						Message:  "fail",
+					},
+				},
+			},
+			expected: "a.md\n" +
+				"- a.md:1:1 https://website.com/error (web, errored: fail)\n" +
+				"- a.md:2:1 https://website.com/warning (web, errored, warning: fail)\n\n",
+		},
+	}
+
+	for i := 0; i < len(tests); i++ {
+		tt := tests[i]
+		t.Run("Should "+tt.message, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			require.NoError(t, Text{}.Report(&buf, Execution{Path: "/docs", Entries: tt.entries, Fixed: tt.fixed}))
+			require.Equal(t, tt.expected, buf.String())
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package scan
+
+import (
+	"bytes"
+	"regexp"
+	"unicode/utf8"
+
+	"nitro/markdown-link-check/internal/service/parser"
+)
+
+const scanDirectiveExpression = `<!--\s*markdown-link-check-(disable-next-line|disable-line|disable|enable)\s*-->`
+
+// Directives available at the Markdown files to disable the verification of the links.
+const (
+	scanDirectiveDisable         = "disable"
+	scanDirectiveEnable          = "enable"
+	scanDirectiveDisableLine     = "disable-line"
+	scanDirectiveDisableNextLine = "disable-next-line"
+)
+
+type scanPosition struct {
+	line   int
+	column int
+}
+
+func (s scanPosition) before(other scanPosition) bool {
+	if s.line != other.line {
+		return s.line < other.line
+	}
+	return s.column < other.column
+}
+
+// scanRange is the part of the document between the disable and enable directives. A disable without an enable goes
+// until the end of the document and has no end.
+type scanRange struct {
+	start scanPosition
+	end   *scanPosition
+}
+
+func (s scanRange) contains(position scanPosition) bool {
+	return s.start.before(position) && ((s.end == nil) || position.before(*s.end))
+}
+
+// scanDirectives holds the parts of the document where the links are disabled.
+type scanDirectives struct {
+	ranges []scanRange
+	lines  map[int]struct{}
+}
+
+// newScanDirectives finds the directives at the payload, which has the code blanked because the directives there are
+// examples.
+func newScanDirectives(regex regexp.Regexp, payload []byte) scanDirectives {
+	directives := scanDirectives{lines: make(map[int]struct{})}
+	var start *scanPosition
+	for _, match := range regex.FindAllSubmatchIndex(payload, -1) {
+		position := scanDirectivePosition(payload, match[0])
+		switch string(payload[match[2]:match[3]]) {
+		case scanDirectiveDisable:
+			if start == nil {
+				start = &position
+			}
+		case scanDirectiveEnable:
+			if start != nil {
+				directives.ranges = append(directives.ranges, scanRange{start: *start, end: &position})
+				start = nil
+			}
+		case scanDirectiveDisableLine:
+			directives.lines[position.line] = struct{}{}
+		case scanDirectiveDisableNextLine:
+			directives.lines[position.line+1] = struct{}{}
+		}
+	}
+	if start != nil {
+		directives.ranges = append(directives.ranges, scanRange{start: *start})
+	}
+	return directives
+}
+
+// disabled checks if the link is disabled. Links without a position are never disabled.
+func (s scanDirectives) disabled(link parser.Link) bool {
+	if link.Line == 0 {
+		return false
+	}
+	if _, ok := s.lines[link.Line]; ok {
+		return true
+	}
+
+	position := scanPosition{line: link.Line, column: link.Column}
+	for _, r := range s.ranges {
+		if r.contains(position) {
+			return true
+		}
+	}
+	return false
+}
+
+func scanDirectivePosition(payload []byte, offset int) scanPosition {
+	lineStart := bytes.LastIndexByte(payload[:offset], '\n') + 1
+	return scanPosition{
+		line:   bytes.Count(payload[:offset], []byte("\n")) + 1,
+		column: utf8.RuneCount(payload[lineStart:offset]) + 1,
+	}
+}
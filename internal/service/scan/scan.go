@@ -1,32 +1,105 @@
 package scan
 
 import (
-	"bytes"
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
 	"sort"
-
-	"github.com/PuerkitoBio/goquery"
+	"strings"
 
 	"nitro/markdown-link-check/internal/service"
+	"nitro/markdown-link-check/internal/service/parser"
 )
 
+// Index holds the relative links between the files. The key is the absolute path of the link target and the value has
+// the files that link to it.
+type Index map[string][]string
+
+// Inbound returns the files that link to any of the paths or to the directories that contain them. The result is
+// sorted and has no duplicates.
+func (i Index) Inbound(paths []string) ([]string, error) {
+	files := make(map[string]struct{})
+	for _, path := range paths {
+		absPath, err := scanResolve(path)
+		if err != nil {
+			return nil, fmt.Errorf("fail to resolve the path '%s': %w", path, err)
+		}
+		for target, sources := range i {
+			if (target != absPath) && !strings.HasPrefix(absPath, target+string(filepath.Separator)) {
+				continue
+			}
+			for _, source := range sources {
+				files[source] = struct{}{}
+			}
+		}
+	}
+
+	result := make([]string, 0, len(files))
+	for file := range files {
+		result = append(result, file)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// scanResolve returns the absolute path with the symbolic links resolved, so the same file reached through different
+// paths is compared equal. The path may not exist, like a deleted file, then only its longest existing parent is
+// resolved.
+func scanResolve(path string) (string, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("fail to expand the path: %w", err)
+	}
+	for parent := absPath; ; parent = filepath.Dir(parent) {
+		resolved, err := filepath.EvalSymlinks(parent)
+		if err == nil {
+			rel, err := filepath.Rel(parent, absPath)
+			if err != nil {
+				return "", fmt.Errorf("fail to find the relative path: %w", err)
+			}
+			return filepath.Join(resolved, rel), nil
+		}
+		if !os.IsNotExist(err) {
+			return "", fmt.Errorf("fail to evaluate the symbolic links: %w", err)
+		}
+		if filepath.Dir(parent) == parent {
+			return absPath, nil
+		}
+	}
+}
+
+// scanProvider is the provider of the findings reported by the scan.
+const scanProvider = "markdown"
+
 type scanParser interface {
-	Do(payload []byte) []byte
+	Links(document parser.Document) []parser.Link
 }
 
 // Scan is responsible for reading, parsing and extracting links from the markdown files.
+//
+// The links can be ignored globally with the 'IgnoreLink' expressions or at the Markdown files with the directives
+// '<!-- markdown-link-check-disable -->' and '<!-- markdown-link-check-enable -->' around the links,
+// '<!-- markdown-link-check-disable-line -->' at the same line or '<!-- markdown-link-check-disable-next-line -->' at
+// the previous line.
+//
+// The references to undefined labels, like '[text][missing]', and the definitions that are never used are reported as
+// findings of the 'markdown' provider, an error and a warning respectively.
+//
+// The files are selected by the 'Extensions', which defaults to '.md'. Files with the '.mdx' extension are
+// preprocessed to remove the ESM statements and to extract the links from the JSX components.
 type Scan struct {
 	IgnoreFile []string
 	IgnoreLink []string
+	Extensions []string
 	Parser     scanParser
 
-	regexFile []regexp.Regexp
-	regexLink []regexp.Regexp
+	regexFile      []regexp.Regexp
+	regexLink      []regexp.Regexp
+	regexDirective regexp.Regexp
 }
 
 // Init the internal state.
@@ -34,6 +107,9 @@ func (s *Scan) Init() error {
 	if s.Parser == nil {
 		return errors.New("missing 'parser'")
 	}
+	if len(s.Extensions) == 0 {
+		s.Extensions = []string{".md"}
+	}
 
 	compile := func(expressions []string) ([]regexp.Regexp, error) {
 		out := make([]regexp.Regexp, 0, len(expressions))
@@ -56,11 +132,17 @@ func (s *Scan) Init() error {
 		return fmt.Errorf("fail to compile ignore link regex: %w", err)
 	}
 
+	regexDirective, err := compile([]string{scanDirectiveExpression})
+	if err != nil {
+		return fmt.Errorf("fail to compile the directive regex: %w", err)
+	}
+	s.regexDirective = regexDirective[0]
+
 	return nil
 }
 
-// Process the directory.
-func (s Scan) Process(path string) ([]service.Entry, error) {
+// Files returns the Markdown files inside the directory sorted by path.
+func (s Scan) Files(path string) ([]string, error) {
 	if err := s.isDir(path); err != nil {
 		return nil, fmt.Errorf("fail to check if path is directory: %w", err)
 	}
@@ -70,7 +152,73 @@ func (s Scan) Process(path string) ([]service.Entry, error) {
 		return nil, fmt.Errorf("fail to fetch the markdown file: %w", err)
 	}
 	sort.Strings(files)
+	return files, nil
+}
+
+// Restrict the files to the ones present at 'paths'. The paths are compared in their absolute form with the symbolic
+// links resolved.
+func (Scan) Restrict(files, paths []string) ([]string, error) {
+	index := make(map[string]struct{}, len(paths))
+	for _, path := range paths {
+		absPath, err := scanResolve(path)
+		if err != nil {
+			return nil, fmt.Errorf("fail to resolve the path '%s': %w", path, err)
+		}
+		index[absPath] = struct{}{}
+	}
 
+	result := make([]string, 0, len(files))
+	for _, file := range files {
+		absFile, err := scanResolve(file)
+		if err != nil {
+			return nil, fmt.Errorf("fail to resolve the path '%s': %w", file, err)
+		}
+		if _, ok := index[absFile]; ok {
+			result = append(result, file)
+		}
+	}
+	return result, nil
+}
+
+// Index builds the reverse index of the relative links at the files. The targets have the symbolic links resolved.
+func (s Scan) Index(files []string) (Index, error) {
+	index := make(Index)
+	for _, file := range files {
+		entries, err := s.processFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("fail to process the file '%s': %w", file, err)
+		}
+
+		absFile, err := scanResolve(file)
+		if err != nil {
+			return nil, fmt.Errorf("fail to resolve the path '%s': %w", file, err)
+		}
+
+		targets := make(map[string]struct{})
+		for _, entry := range entries {
+			if entry.Provider != "" {
+				continue
+			}
+			endpoint, err := url.Parse(entry.Link)
+			if (err != nil) || (endpoint.Scheme != "") || (endpoint.Host != "") || (endpoint.Path == "") {
+				continue
+			}
+			target, err := scanResolve(filepath.Join(filepath.Dir(absFile), endpoint.Path))
+			if err != nil {
+				return nil, fmt.Errorf("fail to resolve the link '%s': %w", entry.Link, err)
+			}
+			if _, ok := targets[target]; ok {
+				continue
+			}
+			targets[target] = struct{}{}
+			index[target] = append(index[target], file)
+		}
+	}
+	return index, nil
+}
+
+// Process the files and extract the links.
+func (s Scan) Process(files []string) ([]service.Entry, error) {
 	result := make([]service.Entry, 0, len(files))
 	for _, file := range files {
 		entries, err := s.processFile(file)
@@ -108,7 +256,7 @@ func (s Scan) listFiles(path string) ([]string, error) {
 			}
 		}
 
-		if filepath.Ext(path) != ".md" {
+		if info.IsDir() || !s.isMarkdown(path) {
 			return nil
 		}
 
@@ -128,54 +276,110 @@ func (s Scan) processFile(path string) ([]service.Entry, error) {
 		return nil, fmt.Errorf("fail to read the file: %w", err)
 	}
 
-	html := s.Parser.Do(payload)
-	links, err := s.extractLinks(html)
-	if err != nil {
-		return nil, fmt.Errorf("fail to extract links: %w", err)
+	var jsxLinks []parser.Link
+	if strings.EqualFold(filepath.Ext(path), ".mdx") {
+		payload, jsxLinks = parser.MDX(payload)
 	}
-	links = s.removeDuplicates(links)
-
-	result := make([]service.Entry, 0, len(links))
+	document := parser.NewDocument(payload)
+	links := s.Parser.Links(document)
+	if len(jsxLinks) > 0 {
+		links = append(links, jsxLinks...)
+		sort.SliceStable(links, func(i, j int) bool {
+			if links[i].Line != links[j].Line {
+				return links[i].Line < links[j].Line
+			}
+			return links[i].Column < links[j].Column
+		})
+	}
+	directives := newScanDirectives(s.regexDirective, document.Code())
+	links = s.filterDirectives(directives, s.filterLinks(links))
+	references := s.filterReferences(directives, document.References())
+	result := make([]service.Entry, 0, len(links)+len(references))
 	for _, link := range links {
-		result = append(result, service.Entry{Path: path, Link: link})
+		result = append(result, service.Entry{
+			Path:   path,
+			Link:   link.Destination,
+			Kind:   link.Kind,
+			Text:   link.Text,
+			Line:   link.Line,
+			Column: link.Column,
+		})
+	}
+	for _, reference := range references {
+		result = append(result, s.referenceEntry(path, reference))
 	}
 
 	return result, nil
 }
 
-func (s Scan) extractLinks(payload []byte) ([]string, error) {
-	doc, err := goquery.NewDocumentFromReader(bytes.NewBuffer(payload))
-	if err != nil {
-		return nil, fmt.Errorf("fail to parse the HTML: %w", err)
+// referenceEntry transforms the broken reference into an entry with the result already set.
+func (Scan) referenceEntry(path string, reference parser.Reference) service.Entry {
+	entry := service.Entry{
+		Path:   path,
+		Link:   reference.Destination,
+		Kind:   reference.Kind,
+		Text:   reference.Label,
+		Line:   reference.Line,
+		Column: reference.Column,
+		Result: service.Result{
+			Severity: service.SeverityWarning,
+			Provider: scanProvider,
+			Reason:   service.ReasonUnusedDefinition,
+			Message:  fmt.Sprintf("definition '[%s]' is never used", reference.Label),
+		},
 	}
+	if reference.Undefined {
+		entry.Link = "[" + reference.Label + "]"
+		entry.Text = reference.Text
+		entry.Result.Severity = service.SeverityError
+		entry.Result.Reason = service.ReasonUndefinedReference
+		entry.Result.Message = fmt.Sprintf("reference '[%s]' is not defined", reference.Label)
+	}
+	return entry
+}
 
-	var links []string
-	doc.Find("a").Each(func(i int, selection *goquery.Selection) {
-		href, ok := selection.Attr("href")
-		if !ok {
-			return
+func (s Scan) isMarkdown(path string) bool {
+	for _, extension := range s.Extensions {
+		if strings.EqualFold(filepath.Ext(path), extension) {
+			return true
 		}
+	}
+	return false
+}
 
+func (s Scan) filterLinks(links []parser.Link) []parser.Link {
+	result := make([]parser.Link, 0, len(links))
+	for _, link := range links {
+		var ignore bool
 		for _, regex := range s.regexLink {
-			if regex.Match([]byte(href)) {
-				return
+			if regex.MatchString(link.Destination) {
+				ignore = true
+				break
 			}
 		}
-
-		links = append(links, href)
-	})
-	return links, nil
+		if !ignore {
+			result = append(result, link)
+		}
+	}
+	return result
 }
 
-func (Scan) removeDuplicates(elements []string) []string {
-	index := make(map[string]struct{})
-	for v := range elements {
-		index[elements[v]] = struct{}{}
+func (Scan) filterDirectives(directives scanDirectives, links []parser.Link) []parser.Link {
+	result := make([]parser.Link, 0, len(links))
+	for _, link := range links {
+		if !directives.disabled(link) {
+			result = append(result, link)
+		}
 	}
+	return result
+}
 
-	result := make([]string, 0, len(index))
-	for key := range index {
-		result = append(result, key)
+func (Scan) filterReferences(directives scanDirectives, references []parser.Reference) []parser.Reference {
+	result := make([]parser.Reference, 0, len(references))
+	for _, reference := range references {
+		if !directives.disabled(reference.Link) {
+			result = append(result, reference)
+		}
 	}
 	return result
 }
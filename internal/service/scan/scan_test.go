@@ -0,0 +1,288 @@
+package scan
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"nitro/markdown-link-check/internal/service"
+	"nitro/markdown-link-check/internal/service/parser"
+)
+
+func TestScanIndex(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "scan")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"README.md":        "[guide](docs/guide.md) [setup](docs/setup.md#install) [site](https://website.com)",
+		"docs/guide.md":    "[setup](./setup.md) [readme](../README.md) [self](#title)",
+		"docs/setup.md":    "[folder](../images/)",
+		"docs/isolated.md": "[mail](mailto:someone@website.com)",
+	}
+	for path, content := range files {
+		path = filepath.Join(dir, "repo", path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+	}
+
+	// The files are scanned through a symbolic link and the paths are queried in their resolved form, like the ones
+	// reported by git.
+	link := dir + "-link"
+	require.NoError(t, os.Symlink(dir, link))
+	defer os.Remove(link)
+	link = filepath.Join(link, "repo")
+	dir, err = filepath.EvalSymlinks(filepath.Join(dir, "repo"))
+	require.NoError(t, err)
+
+	var markdown parser.Markdown
+	markdown.Init()
+	s := Scan{Parser: markdown}
+	require.NoError(t, s.Init())
+
+	paths, err := s.Files(link)
+	require.NoError(t, err)
+	index, err := s.Index(paths)
+	require.NoError(t, err)
+
+	tests := []struct {
+		message  string
+		paths    []string
+		expected []string
+	}{
+		{
+			message:  "find the files linking to a file",
+			paths:    []string{filepath.Join(dir, "docs/setup.md")},
+			expected: []string{filepath.Join(link, "README.md"), filepath.Join(link, "docs/guide.md")},
+		},
+		{
+			message:  "find the files linking to the directory of a file",
+			paths:    []string{filepath.Join(dir, "images/logo.png")},
+			expected: []string{filepath.Join(link, "docs/setup.md")},
+		},
+		{
+			message:  "find nothing for a file without inbound links",
+			paths:    []string{filepath.Join(dir, "docs/isolated.md")},
+			expected: []string{},
+		},
+		{
+			message:  "find the files linking to a deleted file",
+			paths:    []string{filepath.Join(dir, "docs/guide.md"), filepath.Join(dir, "docs/removed.md")},
+			expected: []string{filepath.Join(link, "README.md")},
+		},
+		{
+			message: "find the files linking to multiple files without duplicates",
+			paths:   []string{filepath.Join(dir, "docs/guide.md"), filepath.Join(dir, "docs/setup.md")},
+			expected: []string{
+				filepath.Join(link, "README.md"),
+				filepath.Join(link, "docs/guide.md"),
+			},
+		},
+	}
+
+	for i := 0; i < len(tests); i++ {
+		tt := tests[i]
+		t.Run("Should "+tt.message, func(t *testing.T) {
+			t.Parallel()
+
+			files, err := index.Inbound(tt.paths)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, files)
+		})
+	}
+}
+
+func TestScanRestrict(t *testing.T) {
+	t.Parallel()
+
+	files, err := Scan{}.Restrict(
+		[]string{"/docs/a.md", "/docs/b.md", "/docs/c.md"},
+		[]string{"/docs/c.md", "/docs/../docs/a.md", "/docs/d.md"},
+	)
+	require.NoError(t, err)
+	require.Equal(t, []string{"/docs/a.md", "/docs/c.md"}, files)
+
+	dir, err := ioutil.TempDir("", "scan")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	dir, err = filepath.EvalSymlinks(dir)
+	require.NoError(t, err)
+	link := dir + "-link"
+	require.NoError(t, os.Symlink(dir, link))
+	defer os.Remove(link)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "a.md"), []byte("# A"), 0600))
+
+	files, err = Scan{}.Restrict([]string{filepath.Join(link, "a.md")}, []string{filepath.Join(dir, "a.md")})
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join(link, "a.md")}, files)
+}
+
+func TestScanFilterDirectives(t *testing.T) {
+	t.Parallel()
+
+	var markdown parser.Markdown
+	markdown.Init()
+	s := Scan{Parser: markdown}
+	require.NoError(t, s.Init())
+
+	tests := []struct {
+		message  string
+		payload  string
+		expected []string
+	}{
+		{
+			message:  "keep the links without directives",
+			payload:  "[a](a.md)\n\n[b](b.md)",
+			expected: []string{"a.md", "b.md"},
+		},
+		{
+			message: "ignore the links between the disable and enable directives",
+			payload: "[a](a.md)\n\n<!-- markdown-link-check-disable -->\n[b](b.md)\n\n[c](c.md)\n" +
+				"<!-- markdown-link-check-enable -->\n\n[d](d.md)",
+			expected: []string{"a.md", "d.md"},
+		},
+		{
+			message:  "ignore the links after a disable directive without an enable",
+			payload:  "[a](a.md)\n\n<!--markdown-link-check-disable-->\n\n[b](b.md)",
+			expected: []string{"a.md"},
+		},
+		{
+			message:  "ignore the links between inline directives",
+			payload:  "[a](a.md) <!-- markdown-link-check-disable --> [b](b.md) <!-- markdown-link-check-enable --> [c](c.md)",
+			expected: []string{"a.md", "c.md"},
+		},
+		{
+			message:  "ignore the links at the same line",
+			payload:  "[a](a.md) [b](b.md) <!-- markdown-link-check-disable-line -->\n[c](c.md)",
+			expected: []string{"c.md"},
+		},
+		{
+			message:  "ignore the links at the next line",
+			payload:  "<!-- markdown-link-check-disable-next-line -->\n[a](a.md)\n[b](b.md)",
+			expected: []string{"b.md"},
+		},
+		{
+			message: "ignore the directives at the code",
+			payload: "Use `<!-- markdown-link-check-disable -->` to disable.\n\n```\n<!-- markdown-link-check-disable -->\n```\n\n" +
+				"[a](a.md)",
+			expected: []string{"a.md"},
+		},
+		{
+			message:  "ignore the links at the next line with the same link at the code",
+			payload:  "```\n[a](a.md)\n```\n\n<!-- markdown-link-check-disable-next-line -->\n[a](a.md)\n[b](b.md)",
+			expected: []string{"b.md"},
+		},
+	}
+
+	for i := 0; i < len(tests); i++ {
+		tt := tests[i]
+		t.Run("Should "+tt.message, func(t *testing.T) {
+			t.Parallel()
+
+			document := parser.NewDocument([]byte(tt.payload))
+			directives := newScanDirectives(s.regexDirective, document.Code())
+			links := make([]string, 0)
+			for _, link := range s.filterDirectives(directives, markdown.Links(document)) {
+				links = append(links, link.Destination)
+			}
+			require.Equal(t, tt.expected, links)
+		})
+	}
+}
+
+func TestScanProcess(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "scan")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"a.md":       "[a](a.txt)",
+		"b.markdown": "[b](b.txt)",
+		"c.mdx":      "import Card from './card.js'\n\n[c](c.txt)\n\n<Card href=\"card.md\" />\n\n[d](d.txt)",
+		"d.txt":      "[ignored](ignored.txt)",
+	}
+	for path, content := range files {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, path), []byte(content), 0600))
+	}
+
+	var markdown parser.Markdown
+	markdown.Init()
+	s := Scan{Parser: markdown, Extensions: []string{".md", ".markdown", ".mdx"}}
+	require.NoError(t, s.Init())
+
+	paths, err := s.Files(dir)
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		[]string{filepath.Join(dir, "a.md"), filepath.Join(dir, "b.markdown"), filepath.Join(dir, "c.mdx")},
+		paths,
+	)
+
+	entries, err := s.Process(paths)
+	require.NoError(t, err)
+
+	links := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		links = append(links, fmt.Sprintf("%s:%d:%d", entry.Link, entry.Line, entry.Column))
+	}
+	require.Equal(t, []string{"a.txt:1:5", "b.txt:1:5", "c.txt:3:5", "card.md:5:13", "d.txt:7:5"}, links)
+}
+
+func TestScanProcessReferences(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "scan")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "a.md")
+	payload := "The [link][missing].\n\n<!-- markdown-link-check-disable-next-line -->\nThe [ignored][missing].\n\n" +
+		"[unused]: b.md"
+	require.NoError(t, ioutil.WriteFile(path, []byte(payload), 0600))
+
+	var markdown parser.Markdown
+	markdown.Init()
+	s := Scan{Parser: markdown}
+	require.NoError(t, s.Init())
+
+	entries, err := s.Process([]string{path})
+	require.NoError(t, err)
+	require.Equal(t, []service.Entry{
+		{
+			Path:   path,
+			Link:   "[missing]",
+			Kind:   service.KindLink,
+			Text:   "link",
+			Line:   1,
+			Column: 11,
+			Result: service.Result{
+				Severity: service.SeverityError,
+				Provider: "markdown",
+				Reason:   service.ReasonUndefinedReference,
+				Message:  "reference '[missing]' is not defined",
+			},
+		},
+		{
+			Path:   path,
+			Link:   "b.md",
+			Kind:   service.KindLink,
+			Text:   "unused",
+			Line:   6,
+			Column: 1,
+			Result: service.Result{
+				Severity: service.SeverityWarning,
+				Provider: "markdown",
+				Reason:   service.ReasonUnusedDefinition,
+				Message:  "definition '[unused]' is never used",
+			},
+		},
+	}, entries)
+}
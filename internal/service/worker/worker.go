@@ -4,17 +4,43 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"nitro/markdown-link-check/internal/service"
 )
 
+const workerDefaultConcurrency = 10
+
+// Policies available to handle the entries that could not be verified because of a provider error.
+const (
+	ErrorPolicyInvalid = "invalid"
+	ErrorPolicyWarning = "warning"
+	ErrorPolicyAbort   = "abort"
+)
+
 // Provider represents the providers resonsible to process the entries.
 type Provider interface {
+	Name() string
 	Authority(uri string) bool
-	Valid(ctx context.Context, filePath, uri string) (bool, error)
+	Valid(ctx context.Context, filePath, uri string) (service.Result, error)
 } // nolint: golint
 
+// Resolver is an optional interface for the providers with links relative to the file. The resolved link identifies
+// the resource, so the same resource referenced from different files is verified once.
+type Resolver interface {
+	Resolve(filePath, uri string) string
+}
+
+// Cache holds the results from previous executions. The results are keyed by the resolved link when the provider is a
+// Resolver, so the same link at different files is not mistaken for the same resource.
+type Cache interface {
+	Get(provider, uri string) (service.Result, bool)
+	Set(provider, uri string, result service.Result)
+}
+
 type workerError struct {
 	units []workerErrorUnit
 }
@@ -36,42 +62,267 @@ type workerErrorUnit struct {
 	entry service.Entry
 }
 
+// workerUnit holds the entries that reference the same resource. The resource is verified once by the provider and
+// the result is shared by all the entries.
+type workerUnit struct {
+	provider Provider
+	link     string
+	indexes  []int
+	result   service.Result
+	err      error
+}
+
 // Worker process the entries to check if they're valid. Everything is basead on providers and they're executed in
 // order.
 type Worker struct {
 	Providers []Provider
+
+	// The entries are processed concurrently, bounded by 'Concurrency' and by the per provider limits at
+	// 'ProviderConcurrency', keyed by the provider name.
+	Concurrency         int
+	ProviderConcurrency map[string]int
+
+	// The entries that a provider fails to verify are reported as invalid, the default, as warnings or abort the
+	// processing.
+	ErrorPolicy string
+
+	// The cached results are used instead of calling the providers and the new results are stored when set.
+	Cache Cache
 }
 
-// Process the entries.
+// Process the entries. The result order is the same as the input order, each distinct link is verified once per
+// provider and the entries with the 'Provider' already set are kept as they are.
 func (w Worker) Process(ctx context.Context, entries []service.Entry) ([]service.Entry, error) {
 	if len(w.Providers) == 0 {
 		return nil, errors.New("missing 'providers'")
 	}
 
+	switch w.ErrorPolicy {
+	case "", ErrorPolicyInvalid, ErrorPolicyWarning, ErrorPolicyAbort:
+	default:
+		return nil, fmt.Errorf("unknown error policy '%s'", w.ErrorPolicy)
+	}
+
+	// The verification is cancelled at the first provider error with the abort policy.
+	verifyCtx, verifyCancel := context.WithCancel(ctx)
+	defer verifyCancel()
+
+	var (
+		units      = w.group(entries)
+		semaphores = w.semaphores()
+		slots      = make(chan struct{}, w.concurrency())
+		wg         sync.WaitGroup
+		mutex      sync.Mutex
+		errors     []workerErrorUnit
+	)
+
+	// The units are dispatched per provider and each unit waits for its provider limit before taking a slot, so the
+	// slots are only held by the units that are running and a provider at its limit doesn't block the others.
+	for _, indexes := range w.dispatch(units) {
+		wg.Add(1)
+		go func(indexes []int) {
+			defer wg.Done()
+			for _, index := range indexes {
+				unit := &units[index]
+				release, ok := w.acquire(verifyCtx, semaphores[unit.provider.Name()], slots)
+				if !ok {
+					return
+				}
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					defer release()
+					entry := entries[unit.indexes[0]]
+					unit.result, unit.err = w.process(verifyCtx, entry, unit.link, unit.provider)
+					if (unit.err == nil) || (w.ErrorPolicy != ErrorPolicyAbort) {
+						return
+					}
+
+					// The errors caused by the cancellation itself are not reported.
+					mutex.Lock()
+					defer mutex.Unlock()
+					if verifyCtx.Err() == nil {
+						errors = append(errors, workerErrorUnit{err: unit.err, entry: entry})
+						verifyCancel()
+					}
+				}()
+			}
+		}(indexes)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("processing interrupted: %w", err)
+	}
+
+	if len(errors) > 0 {
+		return nil, workerError{units: errors}
+	}
+
 	var (
-		errors []workerErrorUnit
-		result []service.Entry
+		indexes []int
+		results = make(map[int]service.Result, len(entries))
 	)
+	for _, unit := range units {
+		for _, index := range unit.indexes {
+			results[index] = unit.result
+			indexes = append(indexes, index)
+		}
+	}
+
+	sort.Ints(indexes)
+	result := make([]service.Entry, 0, len(indexes))
+	for _, index := range indexes {
+		entry := entries[index]
+		entry.Result = results[index]
+		result = append(result, entry)
+	}
+	return result, nil
+}
 
-	for _, entry := range entries {
+// group the entries by the provider with authority over them and the resolved link. Entries without a provider with
+// authority are discarded. Entries that already have a result, like the findings from the scan, are kept in units
+// without a provider and they're not verified again.
+func (w Worker) group(entries []service.Entry) []workerUnit {
+	var (
+		units []workerUnit
+		keys  = make(map[string]int)
+	)
+	for i, entry := range entries {
+		if entry.Provider != "" {
+			units = append(units, workerUnit{indexes: []int{i}, result: entry.Result})
+			continue
+		}
 		for _, provider := range w.Providers {
 			if !provider.Authority(entry.Link) {
 				continue
 			}
 
-			valid, err := provider.Valid(ctx, entry.Path, entry.Link)
-			if err != nil {
-				errors = append(errors, workerErrorUnit{err: err, entry: entry})
-			} else {
-				entry.Valid = valid
-				result = append(result, entry)
+			link := entry.Link
+			if resolver, ok := provider.(Resolver); ok {
+				link = resolver.Resolve(entry.Path, entry.Link)
 			}
+
+			key := provider.Name() + "|" + link
+			index, ok := keys[key]
+			if !ok {
+				index = len(units)
+				keys[key] = index
+				units = append(units, workerUnit{provider: provider, link: link})
+			}
+			units[index].indexes = append(units[index].indexes, i)
 			break
 		}
 	}
+	return units
+}
+
+// process verifies the entry with the provider, 'link' is the resolved link used as the cache key.
+func (w Worker) process(ctx context.Context, entry service.Entry, link string, provider Provider) (service.Result, error) {
+	if w.Cache != nil {
+		if result, ok := w.Cache.Get(provider.Name(), link); ok {
+			result.Provider = provider.Name()
+			return result, nil
+		}
+	}
+
+	start := time.Now()
+	result, err := provider.Valid(ctx, entry.Path, entry.Link)
+	if err != nil {
+		result = service.Result{
+			Errored:  true,
+			Severity: w.errorSeverity(),
+			Reason:   service.ReasonErrored,
+			Message:  err.Error(),
+		}
+	} else if !result.Valid && (result.Severity == "") {
+		result.Severity = service.SeverityError
+	}
+	result.Provider = provider.Name()
+	result.Duration = time.Since(start)
+	if (err == nil) && (w.Cache != nil) {
+		w.Cache.Set(provider.Name(), link, result)
+	}
+	return result, err
+}
+
+// dispatch returns the indexes of the units to be verified grouped by provider, in the same order they appear.
+func (Worker) dispatch(units []workerUnit) [][]int {
+	var (
+		result    [][]int
+		providers = make(map[string]int)
+	)
+	for i, unit := range units {
+		if unit.provider == nil {
+			continue
+		}
+		index, ok := providers[unit.provider.Name()]
+		if !ok {
+			index = len(result)
+			providers[unit.provider.Name()] = index
+			result = append(result, nil)
+		}
+		result[index] = append(result[index], i)
+	}
+	return result
+}
 
-	if len(errors) == 0 {
-		return result, nil
+// acquire waits for the provider semaphore, when the provider has a limit, and then for a slot. The function returned
+// releases both of them and it's false when the context is done before they're acquired.
+func (Worker) acquire(ctx context.Context, semaphore, slots chan struct{}) (func(), bool) {
+	if semaphore != nil {
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+			return nil, false
+		}
+	}
+	release := func() {
+		if semaphore != nil {
+			<-semaphore
+		}
+	}
+
+	select {
+	case slots <- struct{}{}:
+	case <-ctx.Done():
+		release()
+		return nil, false
+	}
+	// The slot may be acquired even when the context is done at the same time.
+	if ctx.Err() != nil {
+		<-slots
+		release()
+		return nil, false
+	}
+	return func() {
+		<-slots
+		release()
+	}, true
+}
+
+func (w Worker) errorSeverity() service.Severity {
+	if w.ErrorPolicy == ErrorPolicyWarning {
+		return service.SeverityWarning
+	}
+	return service.SeverityError
+}
+
+func (w Worker) concurrency() int {
+	if w.Concurrency <= 0 {
+		return workerDefaultConcurrency
+	}
+	return w.Concurrency
+}
+
+// semaphores creates a semaphore for each provider that has a concurrency limit.
+func (w Worker) semaphores() map[string]chan struct{} {
+	result := make(map[string]chan struct{}, len(w.ProviderConcurrency))
+	for name, limit := range w.ProviderConcurrency {
+		if limit <= 0 {
+			continue
+		}
+		result[name] = make(chan struct{}, limit)
 	}
-	return nil, workerError{units: errors}
+	return result
 }
@@ -0,0 +1,414 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"nitro/markdown-link-check/internal/service"
+)
+
+func TestWorkerProcess(t *testing.T) {
+	t.Parallel()
+
+	genEntries := func(size int, prefix string) []service.Entry {
+		entries := make([]service.Entry, 0, size)
+		for i := 0; i < size; i++ {
+			entries = append(entries, service.Entry{Path: "file.md", Link: fmt.Sprintf("%s%d", prefix, i)})
+		}
+		return entries
+	}
+
+	tests := []struct {
+		message   string
+		ctx       func() context.Context
+		worker    func() (Worker, *workerProviderMock)
+		entries   []service.Entry
+		expected  []service.Entry
+		limit     int32
+		calls     int32
+		shouldErr bool
+	}{
+		{
+			message: "have an error because of missing providers",
+			ctx:     context.Background,
+			worker: func() (Worker, *workerProviderMock) {
+				return Worker{}, &workerProviderMock{}
+			},
+			shouldErr: true,
+		},
+		{
+			message: "keep the order of the entries",
+			ctx:     context.Background,
+			worker: func() (Worker, *workerProviderMock) {
+				provider := &workerProviderMock{name: "mock", prefix: "valid", delay: time.Millisecond}
+				return Worker{Providers: []Provider{provider}, Concurrency: 5}, provider
+			},
+			entries: genEntries(50, "valid"),
+			expected: func() []service.Entry {
+				entries := genEntries(50, "valid")
+				for i := range entries {
+					entries[i].Result = service.Result{Valid: true, Provider: "mock"}
+				}
+				return entries
+			}(),
+			shouldErr: false,
+		},
+		{
+			message: "respect the provider concurrency limit",
+			ctx:     context.Background,
+			worker: func() (Worker, *workerProviderMock) {
+				provider := &workerProviderMock{name: "mock", prefix: "valid", delay: time.Millisecond}
+				worker := Worker{
+					Providers:           []Provider{provider},
+					Concurrency:         10,
+					ProviderConcurrency: map[string]int{"mock": 2},
+				}
+				return worker, provider
+			},
+			entries: genEntries(20, "valid"),
+			expected: func() []service.Entry {
+				entries := genEntries(20, "valid")
+				for i := range entries {
+					entries[i].Result = service.Result{Valid: true, Provider: "mock"}
+				}
+				return entries
+			}(),
+			limit:     2,
+			shouldErr: false,
+		},
+		{
+			message: "discard the entries without a provider with authority",
+			ctx:     context.Background,
+			worker: func() (Worker, *workerProviderMock) {
+				provider := &workerProviderMock{name: "mock", prefix: "valid", authority: "valid"}
+				return Worker{Providers: []Provider{provider}}, provider
+			},
+			entries: append(genEntries(1, "valid"), genEntries(1, "unknown")...),
+			expected: []service.Entry{
+				{Path: "file.md", Link: "valid0", Result: service.Result{Valid: true, Provider: "mock"}},
+			},
+			shouldErr: false,
+		},
+		{
+			message: "keep the entries that already have a result",
+			ctx:     context.Background,
+			worker: func() (Worker, *workerProviderMock) {
+				provider := &workerProviderMock{name: "mock", prefix: "valid"}
+				return Worker{Providers: []Provider{provider}}, provider
+			},
+			entries: []service.Entry{
+				{Path: "file.md", Link: "valid0"},
+				{
+					Path:   "file.md",
+					Link:   "[missing]",
+					Result: service.Result{Severity: service.SeverityError, Provider: "markdown"},
+				},
+			},
+			expected: []service.Entry{
+				{Path: "file.md", Link: "valid0", Result: service.Result{Valid: true, Provider: "mock"}},
+				{
+					Path:   "file.md",
+					Link:   "[missing]",
+					Result: service.Result{Severity: service.SeverityError, Provider: "markdown"},
+				},
+			},
+			calls:     1,
+			shouldErr: false,
+		},
+		{
+			message: "mark the entries as invalid because of a provider error",
+			ctx:     context.Background,
+			worker: func() (Worker, *workerProviderMock) {
+				provider := &workerProviderMock{name: "mock", prefix: "valid", shouldErr: true}
+				return Worker{Providers: []Provider{provider}}, provider
+			},
+			entries: genEntries(1, "valid"),
+			expected: []service.Entry{
+				{
+					Path: "file.md",
+					Link: "valid0",
+					Result: service.Result{
+						Errored:  true,
+						Severity: service.SeverityError,
+						Provider: "mock",
+						Reason:   service.ReasonErrored,
+						Message:  "failed to check the link",
+					},
+				},
+			},
+			shouldErr: false,
+		},
+		{
+			message: "mark the entries as warning because of a provider error",
+			ctx:     context.Background,
+			worker: func() (Worker, *workerProviderMock) {
+				provider := &workerProviderMock{name: "mock", prefix: "valid", shouldErr: true}
+				return Worker{Providers: []Provider{provider}, ErrorPolicy: ErrorPolicyWarning}, provider
+			},
+			entries: genEntries(1, "valid"),
+			expected: []service.Entry{
+				{
+					Path: "file.md",
+					Link: "valid0",
+					Result: service.Result{
+						Errored:  true,
+						Severity: service.SeverityWarning,
+						Provider: "mock",
+						Reason:   service.ReasonErrored,
+						Message:  "failed to check the link",
+					},
+				},
+			},
+			shouldErr: false,
+		},
+		{
+			message: "have an error because of a provider error with the abort policy without verifying the rest",
+			ctx:     context.Background,
+			worker: func() (Worker, *workerProviderMock) {
+				provider := &workerProviderMock{name: "mock", prefix: "valid", shouldErr: true}
+				return Worker{Providers: []Provider{provider}, Concurrency: 1, ErrorPolicy: ErrorPolicyAbort}, provider
+			},
+			entries:   genEntries(10, "valid"),
+			calls:     1,
+			shouldErr: true,
+		},
+		{
+			message: "have an error because of an unknown error policy",
+			ctx:     context.Background,
+			worker: func() (Worker, *workerProviderMock) {
+				provider := &workerProviderMock{name: "mock", prefix: "valid"}
+				return Worker{Providers: []Provider{provider}, ErrorPolicy: "ignore"}, provider
+			},
+			entries:   genEntries(1, "valid"),
+			shouldErr: true,
+		},
+		{
+			message: "mark the entries not valid with the error severity",
+			ctx:     context.Background,
+			worker: func() (Worker, *workerProviderMock) {
+				provider := &workerProviderMock{name: "mock", prefix: "valid"}
+				return Worker{Providers: []Provider{provider}}, provider
+			},
+			entries: genEntries(1, "invalid"),
+			expected: []service.Entry{
+				{
+					Path:   "file.md",
+					Link:   "invalid0",
+					Result: service.Result{Severity: service.SeverityError, Provider: "mock"},
+				},
+			},
+			shouldErr: false,
+		},
+		{
+			message: "use the cached results and cache the new ones",
+			ctx:     context.Background,
+			worker: func() (Worker, *workerProviderMock) {
+				provider := &workerProviderMock{name: "mock", prefix: "valid"}
+				cache := &workerCacheMock{results: map[string]service.Result{"mock|invalid0": {Valid: true}}}
+				return Worker{Providers: []Provider{provider}, Cache: cache}, provider
+			},
+			entries: append(genEntries(1, "invalid"), genEntries(1, "valid")...),
+			expected: []service.Entry{
+				{Path: "file.md", Link: "invalid0", Result: service.Result{Valid: true, Provider: "mock"}},
+				{Path: "file.md", Link: "valid0", Result: service.Result{Valid: true, Provider: "mock"}},
+			},
+			shouldErr: false,
+		},
+		{
+			message: "cache the results by the resolved link",
+			ctx:     context.Background,
+			worker: func() (Worker, *workerProviderMock) {
+				provider := &workerProviderMock{name: "mock", prefix: "valid"}
+				cache := &workerCacheMock{results: map[string]service.Result{"mock|a/invalid.md": {Valid: true}}}
+				return Worker{Providers: []Provider{workerResolverMock{provider}}, Cache: cache}, provider
+			},
+			entries: []service.Entry{
+				{Path: "a/file.md", Link: "invalid.md"},
+				{Path: "a/b/file.md", Link: "invalid.md"},
+			},
+			expected: []service.Entry{
+				{Path: "a/file.md", Link: "invalid.md", Result: service.Result{Valid: true, Provider: "mock"}},
+				{
+					Path:   "a/b/file.md",
+					Link:   "invalid.md",
+					Result: service.Result{Severity: service.SeverityError, Provider: "mock"},
+				},
+			},
+			calls:     1,
+			shouldErr: false,
+		},
+		{
+			message: "verify each distinct link once",
+			ctx:     context.Background,
+			worker: func() (Worker, *workerProviderMock) {
+				provider := &workerProviderMock{name: "mock", prefix: "valid"}
+				return Worker{Providers: []Provider{workerResolverMock{provider}}}, provider
+			},
+			entries: []service.Entry{
+				{Path: "a/file.md", Link: "valid.md"},
+				{Path: "a/b/file.md", Link: "../valid.md"},
+				{Path: "file.md", Link: "valid.md"},
+				{Path: "a/file.md", Link: "valid.md"},
+			},
+			expected: []service.Entry{
+				{Path: "a/file.md", Link: "valid.md", Result: service.Result{Valid: true, Provider: "mock"}},
+				{Path: "a/b/file.md", Link: "../valid.md", Result: service.Result{Valid: true, Provider: "mock"}},
+				{Path: "file.md", Link: "valid.md", Result: service.Result{Valid: true, Provider: "mock"}},
+				{Path: "a/file.md", Link: "valid.md", Result: service.Result{Valid: true, Provider: "mock"}},
+			},
+			calls:     2,
+			shouldErr: false,
+		},
+		{
+			message: "have an error because of the context cancellation",
+			ctx: func() context.Context {
+				ctx, ctxCancel := context.WithCancel(context.Background())
+				ctxCancel()
+				return ctx
+			},
+			worker: func() (Worker, *workerProviderMock) {
+				provider := &workerProviderMock{name: "mock", prefix: "valid"}
+				return Worker{Providers: []Provider{provider}}, provider
+			},
+			entries:   genEntries(3, "valid"),
+			shouldErr: true,
+		},
+	}
+
+	for i := 0; i < len(tests); i++ {
+		tt := tests[i]
+		t.Run("Should "+tt.message, func(t *testing.T) {
+			t.Parallel()
+
+			worker, provider := tt.worker()
+			result, err := worker.Process(tt.ctx(), tt.entries)
+			require.Equal(t, tt.shouldErr, (err != nil))
+			if tt.calls > 0 {
+				require.Equal(t, tt.calls, provider.calls)
+			}
+			if err != nil {
+				return
+			}
+			for i := range result {
+				result[i].Duration = 0
+			}
+			require.Equal(t, tt.expected, result)
+			if tt.limit > 0 {
+				require.LessOrEqual(t, provider.maxActive, tt.limit)
+			}
+		})
+	}
+}
+
+func TestWorkerProcessProviderConcurrency(t *testing.T) {
+	t.Parallel()
+
+	var (
+		slow    = &workerProviderMock{name: "slow", prefix: "slow", authority: "slow", delay: 100 * time.Millisecond}
+		fast    = &workerProviderMock{name: "fast", prefix: "fast", authority: "fast"}
+		entries []service.Entry
+	)
+	for i := 0; i < 3; i++ {
+		entries = append(entries, service.Entry{Path: "file.md", Link: fmt.Sprintf("slow%d", i)})
+	}
+	for i := 0; i < 10; i++ {
+		entries = append(entries, service.Entry{Path: "file.md", Link: fmt.Sprintf("fast%d", i)})
+	}
+
+	worker := Worker{
+		Providers:           []Provider{slow, fast},
+		Concurrency:         2,
+		ProviderConcurrency: map[string]int{"slow": 1},
+	}
+	start := time.Now()
+	result, err := worker.Process(context.Background(), entries)
+	require.NoError(t, err)
+	require.Len(t, result, len(entries))
+
+	// The slow provider at its limit doesn't hold the slots, so the fast entries finish while the first slow entry is
+	// still running.
+	require.Less(t, int64(fast.last.Sub(start)), int64(slow.delay))
+	require.Equal(t, int32(1), slow.maxActive)
+}
+
+type workerProviderMock struct {
+	name      string
+	prefix    string
+	authority string
+	delay     time.Duration
+	shouldErr bool
+
+	mutex     sync.Mutex
+	active    int32
+	maxActive int32
+	calls     int32
+	last      time.Time
+}
+
+func (w *workerProviderMock) Name() string {
+	return w.name
+}
+
+func (w *workerProviderMock) Authority(uri string) bool {
+	return strings.HasPrefix(uri, w.authority)
+}
+
+func (w *workerProviderMock) Valid(ctx context.Context, _, uri string) (service.Result, error) {
+	if err := ctx.Err(); err != nil {
+		return service.Result{}, err
+	}
+	atomic.AddInt32(&w.calls, 1)
+	if w.shouldErr {
+		return service.Result{}, errors.New("failed to check the link")
+	}
+
+	active := atomic.AddInt32(&w.active, 1)
+	defer atomic.AddInt32(&w.active, -1)
+	w.mutex.Lock()
+	if active > w.maxActive {
+		w.maxActive = active
+	}
+	w.mutex.Unlock()
+
+	time.Sleep(w.delay)
+	w.mutex.Lock()
+	w.last = time.Now()
+	w.mutex.Unlock()
+	return service.Result{Valid: strings.HasPrefix(uri, w.prefix)}, nil
+}
+
+// workerResolverMock resolves the links relative to the file directory.
+type workerResolverMock struct {
+	*workerProviderMock
+}
+
+func (workerResolverMock) Resolve(filePath, uri string) string {
+	return filepath.Join(filepath.Dir(filePath), uri)
+}
+
+type workerCacheMock struct {
+	mutex   sync.Mutex
+	results map[string]service.Result
+}
+
+func (w *workerCacheMock) Get(provider, uri string) (service.Result, bool) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	result, ok := w.results[provider+"|"+uri]
+	return result, ok
+}
+
+func (w *workerCacheMock) Set(provider, uri string, result service.Result) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.results[provider+"|"+uri] = result
+}